@@ -0,0 +1,194 @@
+package go_translate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dinhcanh303/go_translate/utils"
+)
+
+const (
+	// AzureBaseUrl is the default global endpoint of Azure AI Translator.
+	AzureBaseUrl = "https://api.cognitive.microsofttranslator.com"
+
+	// AzureMaxItems is the maximum number of texts sent in one Azure request.
+	AzureMaxItems = 100
+
+	// AzureMaxChars is the maximum number of characters sent in one Azure request.
+	AzureMaxChars = 50000
+)
+
+// AzureOptions configures ProviderAzure. Set it as TranslateOptions.ProviderOptions.
+type AzureOptions struct {
+	// SubscriptionKey is sent as Ocp-Apim-Subscription-Key.
+	SubscriptionKey string
+
+	// Region is sent as Ocp-Apim-Subscription-Region; required for regional and multi-service resources.
+	Region string
+
+	// AccessToken returns a Microsoft Entra ID (AAD) bearer token, used instead of SubscriptionKey.
+	AccessToken func(ctx context.Context) (string, error)
+
+	// BaseURL overrides the endpoint, e.g. for sovereign clouds or a custom domain (default AzureBaseUrl).
+	BaseURL string
+
+	// From forces the source language; the optional detected language passed to TranslateText takes precedence.
+	From string
+
+	// TextType is "plain" (default) or "html".
+	TextType string
+
+	// Category selects a custom translator model or domain (default "general").
+	Category string
+
+	// ProfanityAction is "NoAction" (default), "Marked" or "Deleted".
+	ProfanityAction string
+
+	// ProfanityMarker is "Asterisk" (default) or "Tag", used when ProfanityAction is "Marked".
+	ProfanityMarker string
+}
+
+// ProviderName implements ProviderOptions.
+func (AzureOptions) ProviderName() Provider {
+	return ProviderAzure
+}
+
+// AzureTranslateService is a Translator and Detector for the official Azure AI Translator API.
+type AzureTranslateService struct {
+	client *http.Client      // HTTP client used for making API requests
+	opts   *TranslateOptions // Options for configuring the translation service
+	azure  AzureOptions
+}
+
+// NewAzureTranslateService validates the AzureOptions carried by opts and creates the service.
+func NewAzureTranslateService(client *http.Client, opts *TranslateOptions) (*AzureTranslateService, error) {
+	azure, _ := GetProviderOptions[AzureOptions](opts)
+	if azure.SubscriptionKey == "" && azure.AccessToken == nil {
+		return nil, errors.New("azure requires a subscription key or an access token")
+	}
+	if azure.BaseURL == "" {
+		azure.BaseURL = AzureBaseUrl
+	}
+	azure.BaseURL = strings.TrimRight(azure.BaseURL, "/")
+	return &AzureTranslateService{client: client, opts: opts, azure: azure}, nil
+}
+
+// Capabilities reports the features supported by Azure AI Translator.
+func (a *AzureTranslateService) Capabilities() Capabilities {
+	return Capabilities{Batch: true, Detection: true, HTML: true}
+}
+
+// TranslateText translates the texts with Azure AI Translator.
+// Texts are split into requests of at most AzureMaxItems elements and AzureMaxChars characters;
+// a single text longer than AzureMaxChars is rejected before sending.
+func (a *AzureTranslateService) TranslateText(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+	if err := checkAzureLimits(texts); err != nil {
+		return nil, err
+	}
+	params := url.Values{"api-version": {"3.0"}, "to": {target}}
+	from := a.azure.From
+	if source := sourceLanguage(detectedLangCode); source != "" {
+		from = source
+	}
+	if from != "" {
+		params.Set("from", from)
+	}
+	if a.azure.TextType != "" {
+		params.Set("textType", a.azure.TextType)
+	}
+	if a.azure.Category != "" {
+		params.Set("category", a.azure.Category)
+	}
+	if a.azure.ProfanityAction != "" {
+		params.Set("profanityAction", a.azure.ProfanityAction)
+	}
+	if a.azure.ProfanityMarker != "" {
+		params.Set("profanityMarker", a.azure.ProfanityMarker)
+	}
+	results := make([]string, 0, len(texts))
+	for _, chunk := range chunkTexts(texts, AzureMaxItems, AzureMaxChars) {
+		resp, err := a.call(ctx, "/translate", params, texts[chunk.start:chunk.end])
+		if err != nil {
+			return nil, err
+		}
+		translated, err := utils.ExtractTranslatedTextFromMCSEdge(resp)
+		if err != nil {
+			return nil, err
+		}
+		translated, err = checkTranslationCount(texts[chunk.start:chunk.end], translated)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, translated...)
+	}
+	return results, nil
+}
+
+// DetectLanguage returns the detected language code of every text.
+func (a *AzureTranslateService) DetectLanguage(ctx context.Context, texts []string) ([]string, error) {
+	if err := checkAzureLimits(texts); err != nil {
+		return nil, err
+	}
+	languages := make([]string, 0, len(texts))
+	for _, chunk := range chunkTexts(texts, AzureMaxItems, AzureMaxChars) {
+		resp, err := a.call(ctx, "/detect", url.Values{"api-version": {"3.0"}}, texts[chunk.start:chunk.end])
+		if err != nil {
+			return nil, err
+		}
+		var detections []struct {
+			Language string `json:"language"`
+		}
+		if err := json.Unmarshal(resp, &detections); err != nil {
+			return nil, err
+		}
+		for _, detection := range detections {
+			languages = append(languages, detection.Language)
+		}
+	}
+	return checkTranslationCount(texts, languages)
+}
+
+// call sends texts as the [{"Text": ...}] body expected by the Azure translate and detect operations.
+func (a *AzureTranslateService) call(ctx context.Context, path string, params url.Values, texts []string) ([]byte, error) {
+	payload := make([]map[string]string, 0, len(texts))
+	for _, text := range texts {
+		payload = append(payload, map[string]string{"Text": text})
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	headers := map[string]string{"Content-Type": "application/json"}
+	if a.azure.AccessToken != nil {
+		token, err := a.azure.AccessToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		headers["Authorization"] = "Bearer " + token
+	} else {
+		headers["Ocp-Apim-Subscription-Key"] = a.azure.SubscriptionKey
+	}
+	if a.azure.Region != "" {
+		headers["Ocp-Apim-Subscription-Region"] = a.azure.Region
+	}
+	resp, err := doRequest(ctx, a.client, a.opts, "POST", a.azure.BaseURL+path, headers, params, body)
+	if err != nil {
+		return nil, providerError(ProviderAzure, err)
+	}
+	return resp, nil
+}
+
+// checkAzureLimits rejects texts that cannot fit in a single Azure request on their own.
+func checkAzureLimits(texts []string) error {
+	for i, text := range texts {
+		if n := len([]rune(text)); n > AzureMaxChars {
+			return fmt.Errorf("azure: text %d has %d characters, the limit per request is %d", i, n, AzureMaxChars)
+		}
+	}
+	return nil
+}
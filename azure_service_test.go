@@ -0,0 +1,93 @@
+package go_translate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAzureTranslate(t *testing.T) {
+	var batchSizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "secret", r.Header.Get("Ocp-Apim-Subscription-Key"))
+		require.Equal(t, "westeurope", r.Header.Get("Ocp-Apim-Subscription-Region"))
+		require.Equal(t, "3.0", r.URL.Query().Get("api-version"))
+		var payload []map[string]string
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&payload))
+		batchSizes = append(batchSizes, len(payload))
+		switch r.URL.Path {
+		case "/translate":
+			query := r.URL.Query()
+			require.Equal(t, "en", query.Get("from"))
+			require.Equal(t, "html", query.Get("textType"))
+			require.Equal(t, "Marked", query.Get("profanityAction"))
+			var entries []map[string]any
+			for _, item := range payload {
+				entries = append(entries, map[string]any{"translations": []map[string]string{{"text": query.Get("to") + ":" + item["Text"], "to": query.Get("to")}}})
+			}
+			json.NewEncoder(w).Encode(entries)
+		case "/detect":
+			var detections []map[string]any
+			for range payload {
+				detections = append(detections, map[string]any{"language": "en", "score": 1.0})
+			}
+			json.NewEncoder(w).Encode(detections)
+		}
+	}))
+	defer server.Close()
+
+	translator, err := NewTranslator(&TranslateOptions{
+		Provider: ProviderAzure,
+		ProviderOptions: AzureOptions{
+			SubscriptionKey: "secret",
+			Region:          "westeurope",
+			BaseURL:         server.URL,
+			TextType:        "html",
+			ProfanityAction: "Marked",
+		},
+	})
+	require.Nil(t, err)
+
+	var texts []string
+	for i := 0; i < 150; i++ {
+		texts = append(texts, fmt.Sprintf("text %d", i))
+	}
+	result, err := translator.TranslateText(context.Background(), texts, "vi", "en")
+	require.Nil(t, err)
+	require.Len(t, result, 150)
+	require.Equal(t, "vi:text 149", result[149])
+	require.Equal(t, []int{100, 50}, batchSizes)
+
+	languages, err := translator.(Detector).DetectLanguage(context.Background(), []string{"Hello"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"en"}, languages)
+
+	_, err = translator.TranslateText(context.Background(), []string{strings.Repeat("a", AzureMaxChars+1)}, "vi")
+	require.NotNil(t, err)
+}
+
+func TestAzureError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer aad-token", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"code":401000,"message":"The request is not authorized"}}`))
+	}))
+	defer server.Close()
+
+	translator, err := NewTranslator(&TranslateOptions{
+		Provider: ProviderAzure,
+		ProviderOptions: AzureOptions{
+			AccessToken: func(ctx context.Context) (string, error) { return "aad-token", nil },
+			BaseURL:     server.URL,
+		},
+	})
+	require.Nil(t, err)
+	_, err = translator.TranslateText(context.Background(), []string{"Hello"}, "vi")
+	require.ErrorContains(t, err, "The request is not authorized")
+}
@@ -0,0 +1,146 @@
+package go_translate
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// BaiduBaseUrl is the default endpoint of the Baidu Fanyi general translation API.
+	BaiduBaseUrl = "https://fanyi-api.baidu.com"
+
+	// baiduMaxBytes is Baidu's limit on the UTF-8 length of the text of a request.
+	baiduMaxBytes = 6000
+)
+
+// BaiduOptions configures ProviderBaidu. Set it as TranslateOptions.ProviderOptions.
+type BaiduOptions struct {
+	// AppID is the APP ID of the Baidu Fanyi application.
+	AppID string
+
+	// SecretKey is the key used to sign requests; it is never sent.
+	SecretKey string
+
+	// BaseURL overrides the endpoint (e.g., for a test server).
+	BaseURL string
+}
+
+// ProviderName implements ProviderOptions.
+func (BaiduOptions) ProviderName() Provider {
+	return ProviderBaidu
+}
+
+// BaiduTranslateService is a Translator for Baidu Fanyi.
+type BaiduTranslateService struct {
+	client *http.Client      // HTTP client used for making API requests
+	opts   *TranslateOptions // Options for configuring the translation service
+	baidu  BaiduOptions
+}
+
+// NewBaiduTranslateService validates the BaiduOptions carried by opts and creates the service.
+func NewBaiduTranslateService(client *http.Client, opts *TranslateOptions) (*BaiduTranslateService, error) {
+	baidu, _ := GetProviderOptions[BaiduOptions](opts)
+	if baidu.AppID == "" || baidu.SecretKey == "" {
+		return nil, errors.New("baidu requires an app ID and a secret key")
+	}
+	if baidu.BaseURL == "" {
+		baidu.BaseURL = BaiduBaseUrl
+	}
+	baidu.BaseURL = strings.TrimRight(baidu.BaseURL, "/")
+	return &BaiduTranslateService{client: client, opts: opts, baidu: baidu}, nil
+}
+
+// Capabilities reports the features supported by Baidu Fanyi.
+func (b *BaiduTranslateService) Capabilities() Capabilities {
+	return Capabilities{Batch: true}
+}
+
+// TranslateText translates the texts with Baidu Fanyi. Texts are joined with newlines, which Baidu
+// translates line by line, in requests small enough for its 6000 byte limit.
+func (b *BaiduTranslateService) TranslateText(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+	source := sourceLanguage(detectedLangCode)
+	if source == "" {
+		source = "auto"
+	} else {
+		source = baiduLanguageCode(source)
+	}
+	target = baiduLanguageCode(target)
+	return translateJoined(ctx, ProviderBaidu, texts, baiduMaxBytes, byteLength, func(ctx context.Context, joined string) (string, error) {
+		salt := strconv.Itoa(rand.Intn(1 << 30))
+		form := url.Values{
+			"q":     {joined},
+			"from":  {source},
+			"to":    {target},
+			"appid": {b.baidu.AppID},
+			"salt":  {salt},
+			"sign":  {baiduSign(b.baidu.AppID, joined, salt, b.baidu.SecretKey)},
+		}
+		headers := map[string]string{"Content-Type": "application/x-www-form-urlencoded"}
+		var result struct {
+			ErrorCode   string `json:"error_code"`
+			ErrorMsg    string `json:"error_msg"`
+			TransResult []struct {
+				Dst string `json:"dst"`
+			} `json:"trans_result"`
+		}
+		if err := doRequestJSON(ctx, b.client, b.opts, "POST", b.baidu.BaseURL+"/api/trans/vip/translate", headers, nil, []byte(form.Encode()), &result); err != nil {
+			return "", err
+		}
+		// Baidu reports errors in the body of a 200 response.
+		if result.ErrorCode != "" && result.ErrorCode != "52000" {
+			return "", fmt.Errorf("%s: error %s: %s", ProviderBaidu, result.ErrorCode, result.ErrorMsg)
+		}
+		lines := make([]string, 0, len(result.TransResult))
+		for _, line := range result.TransResult {
+			lines = append(lines, line.Dst)
+		}
+		return strings.Join(lines, "\n"), nil
+	})
+}
+
+// baiduSign computes the request signature MD5(appid + q + salt + secret) as a lower-case hex string.
+func baiduSign(appID, query, salt, secret string) string {
+	sum := md5.Sum([]byte(appID + query + salt + secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// baiduLanguages maps base language codes to the codes Baidu uses where they differ.
+var baiduLanguages = map[string]string{
+	"ja": "jp",
+	"ko": "kor",
+	"fr": "fra",
+	"es": "spa",
+	"ar": "ara",
+	"bg": "bul",
+	"et": "est",
+	"da": "dan",
+	"fi": "fin",
+	"ro": "rom",
+	"sl": "slo",
+	"sv": "swe",
+	"vi": "vie",
+}
+
+// baiduLanguageCode maps a language tag to a Baidu language code, e.g. "ko" -> "kor", "zh-TW" -> "cht".
+func baiduLanguageCode(code string) string {
+	base, rest := splitLanguageCode(code)
+	if base == "zh" {
+		switch rest {
+		case "Hant", "TW", "HK", "MO", "Hant-TW", "Hant-HK":
+			return "cht"
+		}
+		return "zh"
+	}
+	if mapped, ok := baiduLanguages[base]; ok {
+		return mapped
+	}
+	return base
+}
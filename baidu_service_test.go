@@ -0,0 +1,53 @@
+package go_translate
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaiduTranslate(t *testing.T) {
+	server := newFixtureServer(t, map[string]fixtureRoute{
+		"/api/trans/vip/translate": {fixture: "baidu_translate.json", check: func(r *http.Request) {
+			assert.Nil(t, r.ParseForm())
+			form := r.PostForm
+			assert.Equal(t, "app-id", form.Get("appid"))
+			assert.Equal(t, "en", form.Get("from"))
+			assert.Equal(t, "zh", form.Get("to"))
+			assert.Equal(t, baiduSign("app-id", form.Get("q"), form.Get("salt"), "secret"), form.Get("sign"))
+		}},
+	})
+
+	translator, err := NewTranslator(&TranslateOptions{
+		Provider:        ProviderBaidu,
+		ProviderOptions: BaiduOptions{AppID: "app-id", SecretKey: "secret", BaseURL: server.URL},
+	})
+	require.Nil(t, err)
+	result, err := translator.TranslateText(context.Background(), []string{"Hello world", "How are you?"}, "zh-CN", "en")
+	require.Nil(t, err)
+	require.Equal(t, []string{"你好，世界", "你好吗？"}, result)
+}
+
+func TestBaiduError(t *testing.T) {
+	server := newFixtureServer(t, map[string]fixtureRoute{
+		"/api/trans/vip/translate": {fixture: "baidu_error.json"},
+	})
+	translator, err := NewTranslator(&TranslateOptions{
+		Provider:        ProviderBaidu,
+		ProviderOptions: BaiduOptions{AppID: "app-id", SecretKey: "wrong", BaseURL: server.URL},
+	})
+	require.Nil(t, err)
+	_, err = translator.TranslateText(context.Background(), []string{"Hello"}, "zh")
+	require.ErrorContains(t, err, "54001")
+}
+
+func TestBaiduSignAndLanguageCode(t *testing.T) {
+	// Example from the Baidu Fanyi documentation.
+	require.Equal(t, "f89f9594663708c1605f3d736d01d2d4", baiduSign("2015063000000001", "apple", "1435660288", "12345678"))
+	require.Equal(t, "kor", baiduLanguageCode("ko"))
+	require.Equal(t, "cht", baiduLanguageCode("zh-TW"))
+	require.Equal(t, "jp", baiduLanguageCode("ja-JP"))
+}
@@ -0,0 +1,152 @@
+package go_translate
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultBatchChunkSize is the number of texts sent per request when BatchOptions.ChunkSize is not set.
+	DefaultBatchChunkSize = 50
+
+	// DefaultBatchChunkChars is the number of characters sent per request when BatchOptions.MaxChunkChars is not set.
+	DefaultBatchChunkChars = 5000
+)
+
+// Progress describes the state of a running batch translation.
+type Progress struct {
+	Done       int           // Number of texts processed so far (translated or failed)
+	Total      int           // Total number of texts in the batch
+	Characters int           // Number of characters processed so far
+	Errors     int           // Number of texts that failed to translate
+	Elapsed    time.Duration // Time spent since the batch started
+	ETA        time.Duration // Estimated remaining time, based on the characters processed so far
+}
+
+// ProgressFunc is called after every chunk of a batch translation.
+type ProgressFunc func(Progress)
+
+// BatchOptions configures TranslateBatch.
+type BatchOptions struct {
+	// ChunkSize is the maximum number of texts sent in a single TranslateText call.
+	ChunkSize int
+
+	// MaxChunkChars is the maximum number of characters sent in a single TranslateText call.
+	// A single text longer than the limit is still sent on its own.
+	MaxChunkChars int
+
+	// OnProgress, if set, receives a progress report after every chunk.
+	OnProgress ProgressFunc
+
+	// ContinueOnError keeps translating the remaining chunks when a chunk fails.
+	// Failed texts are left empty in the result and reported in the returned BatchError.
+	ContinueOnError bool
+}
+
+// BatchError is returned by TranslateBatch when the batch did not complete successfully.
+// The results returned alongside it still contain every text translated before the failure.
+type BatchError struct {
+	Done   int   // Number of texts processed before the batch stopped
+	Failed []int // Indexes of the texts that failed to translate
+	Err    error // The cause: the context error on cancellation, or the last chunk error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch translation stopped after %d texts (%d failed): %v", e.Done, len(e.Failed), e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// TranslateBatch translates a large list of texts with the given Translator by splitting it into chunks.
+//
+// The returned slice always has the same length as texts. When the context is cancelled, or a chunk fails
+// and ContinueOnError is false, the texts translated so far are kept, the rest are left empty,
+// and a *BatchError wrapping the cause is returned.
+func TranslateBatch(ctx context.Context, t Translator, texts []string, target string, opts *BatchOptions, detectedLangCode ...string) ([]string, error) {
+	if opts == nil {
+		opts = &BatchOptions{}
+	}
+	results := make([]string, len(texts))
+	totalChars := 0
+	for _, text := range texts {
+		totalChars += utf8.RuneCountInString(text)
+	}
+	progress := Progress{Total: len(texts)}
+	var failed []int
+	var lastErr error
+	start := time.Now()
+	for _, chunk := range chunkTexts(texts, opts.ChunkSize, opts.MaxChunkChars) {
+		if err := ctx.Err(); err != nil {
+			return results, &BatchError{Done: progress.Done, Failed: failed, Err: err}
+		}
+		translated, err := t.TranslateText(ctx, texts[chunk.start:chunk.end], target, detectedLangCode...)
+		if err == nil && len(translated) != chunk.end-chunk.start {
+			err = fmt.Errorf("expected %d translations, got %d", chunk.end-chunk.start, len(translated))
+		}
+		if err != nil {
+			// A chunk interrupted by cancellation is not a translation failure.
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return results, &BatchError{Done: progress.Done, Failed: failed, Err: ctxErr}
+			}
+			for i := chunk.start; i < chunk.end; i++ {
+				failed = append(failed, i)
+			}
+			progress.Errors += chunk.end - chunk.start
+			lastErr = err
+		} else {
+			copy(results[chunk.start:chunk.end], translated)
+		}
+		progress.Done += chunk.end - chunk.start
+		progress.Characters += chunk.chars
+		progress.Elapsed = time.Since(start)
+		if progress.Characters > 0 {
+			progress.ETA = time.Duration(float64(progress.Elapsed) * float64(totalChars-progress.Characters) / float64(progress.Characters))
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+		if err != nil && !opts.ContinueOnError {
+			return results, &BatchError{Done: progress.Done, Failed: failed, Err: err}
+		}
+	}
+	if len(failed) > 0 {
+		return results, &BatchError{Done: progress.Done, Failed: failed, Err: lastErr}
+	}
+	return results, nil
+}
+
+// textChunk is a half-open range [start, end) of texts sent in one request.
+type textChunk struct {
+	start, end int
+	chars      int
+}
+
+// chunkTexts splits texts into consecutive chunks holding at most maxItems texts and maxChars characters.
+// Non-positive limits fall back to DefaultBatchChunkSize and DefaultBatchChunkChars.
+func chunkTexts(texts []string, maxItems, maxChars int) []textChunk {
+	if maxItems <= 0 {
+		maxItems = DefaultBatchChunkSize
+	}
+	if maxChars <= 0 {
+		maxChars = DefaultBatchChunkChars
+	}
+	var chunks []textChunk
+	current := textChunk{}
+	for i, text := range texts {
+		n := utf8.RuneCountInString(text)
+		if current.end > current.start && (current.end-current.start >= maxItems || current.chars+n > maxChars) {
+			chunks = append(chunks, current)
+			current = textChunk{start: i, end: i}
+		}
+		current.end = i + 1
+		current.chars += n
+	}
+	if current.end > current.start {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
@@ -0,0 +1,92 @@
+package go_translate
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// upperTranslator is a stub Translator that upper-cases its input and fails on texts containing "fail".
+type upperTranslator struct {
+	calls  int
+	onCall func(call int)
+}
+
+func (u *upperTranslator) TranslateText(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+	u.calls++
+	if u.onCall != nil {
+		u.onCall(u.calls)
+	}
+	var out []string
+	for _, text := range texts {
+		if strings.Contains(text, "fail") {
+			return nil, errors.New("stub failure")
+		}
+		out = append(out, strings.ToUpper(text))
+	}
+	return out, nil
+}
+
+func TestTranslateBatch(t *testing.T) {
+	texts := []string{"a", "b", "c", "d", "e"}
+
+	t.Run("progress", func(t *testing.T) {
+		var reports []Progress
+		result, err := TranslateBatch(context.Background(), &upperTranslator{}, texts, "vi", &BatchOptions{
+			ChunkSize:  2,
+			OnProgress: func(p Progress) { reports = append(reports, p) },
+		})
+		require.Nil(t, err)
+		require.Equal(t, []string{"A", "B", "C", "D", "E"}, result)
+		require.Len(t, reports, 3)
+		last := reports[len(reports)-1]
+		require.Equal(t, 5, last.Done)
+		require.Equal(t, 5, last.Total)
+		require.Equal(t, 5, last.Characters)
+		require.Zero(t, last.ETA)
+	})
+
+	t.Run("cancel keeps partial results", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stub := &upperTranslator{onCall: func(call int) {
+			if call == 2 {
+				cancel()
+			}
+		}}
+		result, err := TranslateBatch(ctx, stub, texts, "vi", &BatchOptions{ChunkSize: 2})
+		require.ErrorIs(t, err, context.Canceled)
+		var batchErr *BatchError
+		require.True(t, errors.As(err, &batchErr))
+		require.Equal(t, 4, batchErr.Done)
+		require.Equal(t, []string{"A", "B", "C", "D", ""}, result)
+		require.Equal(t, 2, stub.calls)
+	})
+
+	t.Run("continue on error", func(t *testing.T) {
+		var last Progress
+		result, err := TranslateBatch(context.Background(), &upperTranslator{}, []string{"a", "fail", "c"}, "vi", &BatchOptions{
+			ChunkSize:       1,
+			ContinueOnError: true,
+			OnProgress:      func(p Progress) { last = p },
+		})
+		var batchErr *BatchError
+		require.True(t, errors.As(err, &batchErr))
+		require.Equal(t, []int{1}, batchErr.Failed)
+		require.Equal(t, []string{"A", "", "C"}, result)
+		require.Equal(t, 1, last.Errors)
+	})
+}
+
+func TestChunkTexts(t *testing.T) {
+	chunks := chunkTexts([]string{"aaaa", "bb", "cc", "dddddd", "e"}, 3, 5)
+	require.Equal(t, []textChunk{
+		{start: 0, end: 1, chars: 4},
+		{start: 1, end: 3, chars: 4},
+		{start: 3, end: 4, chars: 6},
+		{start: 4, end: 5, chars: 1},
+	}, chunks)
+}
@@ -0,0 +1,87 @@
+package go_translate
+
+import (
+	"context"
+	"errors"
+)
+
+// Middleware decorates a Translator with a cross-cutting behavior such as caching, deduplication, glossary
+// enforcement, PII masking, logging or fallback. Set them as TranslateOptions.Middlewares or compose them with Chain.
+type Middleware func(next Translator) Translator
+
+// TranslatorFunc adapts a function to the Translator interface.
+type TranslatorFunc func(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error)
+
+// TranslateText calls f.
+func (f TranslatorFunc) TranslateText(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+	return f(ctx, texts, target, detectedLangCode...)
+}
+
+// Chain wraps base with middlewares; the first middleware sees the call first and the result last.
+// The returned Translator reports the capabilities of base and returns base from Unwrap. It implements
+// Detector and LanguageLister when base does, forwarding those calls to base without the middlewares.
+func Chain(base Translator, middlewares ...Middleware) Translator {
+	translator := base
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		translator = middlewares[i](translator)
+	}
+	c := &chain{Translator: translator, base: base}
+	detector, isDetector := base.(Detector)
+	lister, isLister := base.(LanguageLister)
+	switch {
+	case isDetector && isLister:
+		return &struct {
+			*chain
+			Detector
+			LanguageLister
+		}{c, detector, lister}
+	case isDetector:
+		return &struct {
+			*chain
+			Detector
+		}{c, detector}
+	case isLister:
+		return &struct {
+			*chain
+			LanguageLister
+		}{c, lister}
+	}
+	return c
+}
+
+// chain is the Translator returned by Chain.
+type chain struct {
+	Translator
+	base Translator
+}
+
+// Unwrap returns the Translator the chain was built on.
+func (c *chain) Unwrap() Translator {
+	return c.base
+}
+
+// Capabilities reports the capabilities of the Translator the chain was built on.
+func (c *chain) Capabilities() Capabilities {
+	return CapabilitiesOf(c.base)
+}
+
+// FallbackMiddleware tries fallbacks in order when the translation fails, and returns the first successful one.
+// If all fail, the errors are joined; if ctx is done, its error is returned without trying further.
+func FallbackMiddleware(fallbacks ...Translator) Middleware {
+	return func(next Translator) Translator {
+		return TranslatorFunc(func(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+			var errs []error
+			for _, translator := range append([]Translator{next}, fallbacks...) {
+				translated, err := translator.TranslateText(ctx, texts, target, detectedLangCode...)
+				if err == nil {
+					return translated, nil
+				}
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return nil, ctxErr
+				}
+				errs = append(errs, err)
+			}
+			return nil, errors.Join(errs...)
+		})
+	}
+}
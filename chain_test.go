@@ -0,0 +1,138 @@
+package go_translate
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dinhcanh303/go_translate/translatetest"
+	"github.com/stretchr/testify/require"
+)
+
+// tracingMiddleware appends name to trace before and after the call.
+func tracingMiddleware(name string, trace *[]string) Middleware {
+	return func(next Translator) Translator {
+		return TranslatorFunc(func(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+			*trace = append(*trace, name+">")
+			translated, err := next.TranslateText(ctx, texts, target, detectedLangCode...)
+			*trace = append(*trace, name+"<")
+			return translated, err
+		})
+	}
+}
+
+// cachingMiddleware serves repeated single-text calls from memory.
+func cachingMiddleware() Middleware {
+	cache := map[string][]string{}
+	return func(next Translator) Translator {
+		return TranslatorFunc(func(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+			key := target + "\x00" + strings.Join(texts, "\x00")
+			if translated, ok := cache[key]; ok {
+				return translated, nil
+			}
+			translated, err := next.TranslateText(ctx, texts, target, detectedLangCode...)
+			if err == nil {
+				cache[key] = translated
+			}
+			return translated, err
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	ctx := context.Background()
+	var trace []string
+	base := translatetest.NewFake()
+	translator := Chain(base, tracingMiddleware("first", &trace), cachingMiddleware(), tracingMiddleware("last", &trace))
+
+	for i := 0; i < 2; i++ {
+		result, err := translator.TranslateText(ctx, []string{"Hello"}, "vi")
+		require.Nil(t, err)
+		require.Equal(t, []string{"[vi] Hello"}, result)
+	}
+	// The second call is answered by the cache, before reaching the last middleware.
+	require.Equal(t, []string{"first>", "last>", "last<", "first<", "first>", "first<"}, trace)
+	require.Len(t, base.Calls(), 1)
+	require.Equal(t, base, translator.(interface{ Unwrap() Translator }).Unwrap())
+}
+
+func TestChainCapabilities(t *testing.T) {
+	translator, err := NewTranslator(&TranslateOptions{GoogleAPIType: TypeHtml, Middlewares: []Middleware{LoggingMiddleware(discardLogger, "google", false)}})
+	require.Nil(t, err)
+	require.Equal(t, Capabilities{Batch: true, HTML: true}, CapabilitiesOf(translator))
+
+	translator, err = NewTranslator(&TranslateOptions{GoogleAPIType: TypeHtml})
+	require.Nil(t, err)
+	_, ok := translator.(*GoogleTranslateService)
+	require.True(t, ok, "no middlewares, no chain")
+}
+
+func TestChainForwardsDetector(t *testing.T) {
+	server := newLibreTranslateServer(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	translator, err := NewTranslator(&TranslateOptions{
+		Provider:        ProviderLibreTranslate,
+		ProviderOptions: LibreTranslateOptions{BaseURL: server.URL, APIKey: "libre-key"},
+		Middlewares:     []Middleware{LoggingMiddleware(discardLogger, "libretranslate", false)},
+		Tracer:          &recordingTracer{},
+	})
+	require.Nil(t, err)
+	detector, ok := translator.(Detector)
+	require.True(t, ok)
+	languages, err := detector.DetectLanguage(ctx, []string{"Hello"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"en"}, languages)
+	lister, ok := translator.(LanguageLister)
+	require.True(t, ok)
+	supported, err := lister.Languages(ctx)
+	require.Nil(t, err)
+	require.Len(t, supported, 1)
+
+	translator, err = NewTranslator(&TranslateOptions{GoogleAPIType: TypeHtml, Middlewares: []Middleware{LoggingMiddleware(discardLogger, "google", false)}})
+	require.Nil(t, err)
+	_, ok = translator.(Detector)
+	require.False(t, ok, "the Google provider does not detect languages")
+}
+
+func TestFallbackMiddleware(t *testing.T) {
+	primaryErr, fallbackErr := errors.New("primary down"), errors.New("fallback down")
+	tcs := map[string]struct {
+		primary  error
+		fallback error
+		cancel   bool
+		expected []string
+		errs     []error
+	}{
+		"primary answers":     {expected: []string{"[vi] Hello"}},
+		"fallback answers":    {primary: primaryErr, expected: []string{"[vi] Hello"}},
+		"both fail":           {primary: primaryErr, fallback: fallbackErr, errs: []error{primaryErr, fallbackErr}},
+		"cancelled, no retry": {primary: primaryErr, fallback: fallbackErr, cancel: true, errs: []error{context.Canceled}},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			primary, fallback := translatetest.NewFake(), translatetest.NewFake()
+			if tc.primary != nil {
+				primary.FailNext(tc.primary)
+			}
+			if tc.fallback != nil {
+				fallback.FailNext(tc.fallback)
+			}
+			if tc.cancel {
+				cancel()
+			}
+			result, err := Chain(primary, FallbackMiddleware(fallback)).TranslateText(ctx, []string{"Hello"}, "vi")
+			require.Equal(t, tc.expected, result)
+			for _, expected := range tc.errs {
+				require.ErrorIs(t, err, expected)
+			}
+			if tc.cancel {
+				require.Empty(t, fallback.Calls())
+			}
+		})
+	}
+}
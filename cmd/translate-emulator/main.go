@@ -0,0 +1,40 @@
+// Command translate-emulator serves the Google and Microsoft endpoints used by go_translate locally,
+// with deterministic fake translations, for offline development.
+//
+// Usage:
+//
+//	translate-emulator -addr :8080 -latency 50ms -fail-rate 0.1 -fail captcha
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/dinhcanh303/go_translate/emulator"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	latency := flag.Duration("latency", 0, "delay added to every response")
+	failRate := flag.Float64("fail-rate", 0, "fraction of requests answered with a failure (0-1)")
+	failure := flag.String("fail", string(emulator.FailRateLimit), "failure to inject: rate-limit, server-error or captcha")
+	seed := flag.Int64("seed", 1, "seed of the failure injection")
+	source := flag.String("source", "en", "language reported as detected")
+	flag.Parse()
+
+	switch emulator.Failure(*failure) {
+	case emulator.FailRateLimit, emulator.FailServerError, emulator.FailCaptcha:
+	default:
+		log.Fatalf("unknown failure %q", *failure)
+	}
+	handler := emulator.New(emulator.Options{
+		SourceLanguage: *source,
+		Latency:        *latency,
+		FailureRate:    *failRate,
+		Failure:        emulator.Failure(*failure),
+		Seed:           *seed,
+	})
+	log.Printf("translate emulator listening on http://%s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, handler))
+}
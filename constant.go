@@ -1,5 +1,7 @@
 package go_translate
 
+// GoogleUrls holds the default endpoint of every Google API type.
+// Use TranslateOptions.GoogleEndpoints to point a single translator elsewhere.
 var GoogleUrls map[GoogleAPIType]string = map[GoogleAPIType]string{
 	TypeHtml:               "https://translate-pa.googleapis.com/v1/translateHtml",
 	TypeClientDictChromeEx: "/translate_a/t?client=dict-chrome-ex&sl=auto",
@@ -8,10 +10,14 @@ var GoogleUrls map[GoogleAPIType]string = map[GoogleAPIType]string{
 	TypeDictionary:         "https://dictionaryextension-pa.googleapis.com/v1/dictionaryExtensionData?strategy=2",
 }
 
+// MicrosoftUrls holds the default endpoint of every Microsoft API type.
+// Use TranslateOptions.MicrosoftEndpoints to point a single translator elsewhere.
 var MicrosoftUrls map[MicrosoftAPIType]string = map[MicrosoftAPIType]string{
-	TypeEdge: "https://api-edge.cognitive.microsofttranslator.com/translate?api-version=3.0&includeSentenceLength=false&to=",
+	TypeEdge:      "https://api-edge.cognitive.microsofttranslator.com/translate?api-version=3.0&includeSentenceLength=false&to=",
+	TypeSmartLink: MicrosoftServerUrl,
 }
 
+// AuthEdgeUrl is the default URL of the Edge authorization token (see TranslateOptions.MicrosoftAuthURL).
 const AuthEdgeUrl = "https://edge.microsoft.com/translate/auth"
 
 const MicrosoftServerUrl = "https://webmail.smartlinkcorp.com/dotrans_20160909.php"
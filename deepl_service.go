@@ -0,0 +1,170 @@
+package go_translate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const (
+	// DeepLFreeBaseUrl is the endpoint of the DeepL API Free plan.
+	DeepLFreeBaseUrl = "https://api-free.deepl.com"
+
+	// DeepLProBaseUrl is the endpoint of the DeepL API Pro plan.
+	DeepLProBaseUrl = "https://api.deepl.com"
+
+	// DeepLMaxItems is the maximum number of texts sent in one DeepL request.
+	DeepLMaxItems = 50
+
+	deeplMaxChars = 30000
+)
+
+// DeepLOptions configures ProviderDeepL. Set it as TranslateOptions.ProviderOptions.
+type DeepLOptions struct {
+	// AuthKey is the DeepL authentication key. Keys ending in ":fx" select the Free API.
+	AuthKey string
+
+	// UseFreeAPI forces the Free API endpoint for keys that do not end in ":fx".
+	UseFreeAPI bool
+
+	// BaseURL overrides the endpoint selected from the key (e.g., for a test server).
+	BaseURL string
+
+	// Formality is "default", "more", "less", "prefer_more" or "prefer_less".
+	Formality string
+
+	// TagHandling is "xml" or "html" to translate marked-up text.
+	TagHandling string
+
+	// SplitSentences is "0", "1" (default) or "nonewlines".
+	SplitSentences string
+
+	// PreserveFormatting keeps the original punctuation and casing.
+	PreserveFormatting bool
+
+	// GlossaryID is the ID of a DeepL glossary. Glossaries require a source language.
+	GlossaryID string
+}
+
+// ProviderName implements ProviderOptions.
+func (DeepLOptions) ProviderName() Provider {
+	return ProviderDeepL
+}
+
+// DeepLTranslateService is a Translator for the DeepL API.
+type DeepLTranslateService struct {
+	client *http.Client      // HTTP client used for making API requests
+	opts   *TranslateOptions // Options for configuring the translation service
+	deepl  DeepLOptions
+}
+
+// NewDeepLTranslateService validates the DeepLOptions carried by opts and creates the service.
+func NewDeepLTranslateService(client *http.Client, opts *TranslateOptions) (*DeepLTranslateService, error) {
+	deepl, _ := GetProviderOptions[DeepLOptions](opts)
+	if deepl.AuthKey == "" {
+		return nil, errors.New("deepl requires an auth key")
+	}
+	if deepl.BaseURL == "" {
+		deepl.BaseURL = DeepLProBaseUrl
+		if deepl.UseFreeAPI || strings.HasSuffix(deepl.AuthKey, ":fx") {
+			deepl.BaseURL = DeepLFreeBaseUrl
+		}
+	}
+	deepl.BaseURL = strings.TrimRight(deepl.BaseURL, "/")
+	return &DeepLTranslateService{client: client, opts: opts, deepl: deepl}, nil
+}
+
+// Capabilities reports the features supported by DeepL.
+func (d *DeepLTranslateService) Capabilities() Capabilities {
+	return Capabilities{Batch: true, HTML: true, Glossary: true}
+}
+
+// TranslateText translates the texts with DeepL. Language codes are mapped to DeepL codes,
+// e.g. "en-gb" -> "EN-GB", "pt_BR" -> "PT-BR", "zh-TW" -> "ZH-HANT".
+func (d *DeepLTranslateService) TranslateText(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+	payload := map[string]any{"target_lang": deeplLanguageCode(target, true)}
+	if source := sourceLanguage(detectedLangCode); source != "" {
+		payload["source_lang"] = deeplLanguageCode(source, false)
+	}
+	if d.deepl.Formality != "" {
+		payload["formality"] = d.deepl.Formality
+	}
+	if d.deepl.TagHandling != "" {
+		payload["tag_handling"] = d.deepl.TagHandling
+	}
+	if d.deepl.SplitSentences != "" {
+		payload["split_sentences"] = d.deepl.SplitSentences
+	}
+	if d.deepl.PreserveFormatting {
+		payload["preserve_formatting"] = true
+	}
+	if d.deepl.GlossaryID != "" {
+		if _, ok := payload["source_lang"]; !ok {
+			return nil, errors.New("deepl glossaries require a source language")
+		}
+		payload["glossary_id"] = d.deepl.GlossaryID
+	}
+	headers := map[string]string{
+		"Content-Type":  "application/json",
+		"Authorization": "DeepL-Auth-Key " + d.deepl.AuthKey,
+	}
+	results := make([]string, 0, len(texts))
+	for _, chunk := range chunkTexts(texts, DeepLMaxItems, deeplMaxChars) {
+		payload["text"] = texts[chunk.start:chunk.end]
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		var result struct {
+			Translations []struct {
+				Text string `json:"text"`
+			} `json:"translations"`
+		}
+		if err := doRequestJSON(ctx, d.client, d.opts, "POST", d.deepl.BaseURL+"/v2/translate", headers, nil, body, &result); err != nil {
+			return nil, providerError(ProviderDeepL, err)
+		}
+		translated := make([]string, 0, len(result.Translations))
+		for _, t := range result.Translations {
+			translated = append(translated, t.Text)
+		}
+		translated, err = checkTranslationCount(texts[chunk.start:chunk.end], translated)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, translated...)
+	}
+	return results, nil
+}
+
+// deeplLanguageCode maps a language tag to a DeepL language code.
+// Source languages never carry a variant; target languages keep the variants DeepL distinguishes.
+func deeplLanguageCode(code string, target bool) string {
+	base, rest := splitLanguageCode(code)
+	if base == "no" {
+		base = "nb"
+	}
+	if !target {
+		return strings.ToUpper(base)
+	}
+	switch base {
+	case "en":
+		if rest == "GB" {
+			return "EN-GB"
+		}
+		return "EN-US"
+	case "pt":
+		if rest == "BR" {
+			return "PT-BR"
+		}
+		return "PT-PT"
+	case "zh":
+		switch rest {
+		case "Hant", "TW", "HK", "MO", "Hant-TW", "Hant-HK":
+			return "ZH-HANT"
+		}
+		return "ZH-HANS"
+	}
+	return strings.ToUpper(base)
+}
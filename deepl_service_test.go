@@ -0,0 +1,83 @@
+package go_translate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeepLTranslate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v2/translate", r.URL.Path)
+		require.Equal(t, "DeepL-Auth-Key key:fx", r.Header.Get("Authorization"))
+		var payload map[string]any
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&payload))
+		require.Equal(t, "PT-BR", payload["target_lang"])
+		require.Equal(t, "EN", payload["source_lang"])
+		require.Equal(t, "less", payload["formality"])
+		require.Equal(t, "html", payload["tag_handling"])
+		require.Equal(t, "glossary-1", payload["glossary_id"])
+		var translations []map[string]string
+		for _, text := range payload["text"].([]any) {
+			translations = append(translations, map[string]string{"detected_source_language": "EN", "text": "pt:" + text.(string)})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"translations": translations})
+	}))
+	defer server.Close()
+
+	translator, err := NewTranslator(&TranslateOptions{
+		Provider: ProviderDeepL,
+		ProviderOptions: DeepLOptions{
+			AuthKey:     "key:fx",
+			BaseURL:     server.URL,
+			Formality:   "less",
+			TagHandling: "html",
+			GlossaryID:  "glossary-1",
+		},
+	})
+	require.Nil(t, err)
+	result, err := translator.TranslateText(context.Background(), []string{"<b>Hello</b>", "World"}, "pt_br", "en-US")
+	require.Nil(t, err)
+	require.Equal(t, []string{"pt:<b>Hello</b>", "pt:World"}, result)
+}
+
+func TestDeepLBaseURL(t *testing.T) {
+	free, err := NewDeepLTranslateService(nil, &TranslateOptions{ProviderOptions: DeepLOptions{AuthKey: "abc:fx"}})
+	require.Nil(t, err)
+	require.Equal(t, DeepLFreeBaseUrl, free.deepl.BaseURL)
+	pro, err := NewDeepLTranslateService(nil, &TranslateOptions{ProviderOptions: DeepLOptions{AuthKey: "abc"}})
+	require.Nil(t, err)
+	require.Equal(t, DeepLProBaseUrl, pro.deepl.BaseURL)
+}
+
+func TestDeepLLanguageCode(t *testing.T) {
+	tcs := map[string]struct {
+		code     string
+		target   bool
+		expected string
+	}{
+		"british english":      {code: "en-gb", target: true, expected: "EN-GB"},
+		"default english":      {code: "en", target: true, expected: "EN-US"},
+		"brazilian portuguese": {code: "pt_BR", target: true, expected: "PT-BR"},
+		"traditional chinese":  {code: "zh-TW", target: true, expected: "ZH-HANT"},
+		"simplified chinese":   {code: "zh-hans", target: true, expected: "ZH-HANS"},
+		"norwegian":            {code: "no", target: true, expected: "NB"},
+		"source variant":       {code: "en-GB", target: false, expected: "EN"},
+	}
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			require.Equal(t, tc.expected, deeplLanguageCode(tc.code, tc.target))
+		})
+	}
+}
+
+func TestNormalizeLanguageCode(t *testing.T) {
+	require.Equal(t, "en-GB", NormalizeLanguageCode("en_gb"))
+	require.Equal(t, "zh-Hans", NormalizeLanguageCode("ZH-HANS"))
+	require.Equal(t, "zh-Hant-TW", NormalizeLanguageCode("zh_hant_tw"))
+	require.Equal(t, "vi", NormalizeLanguageCode(" VI "))
+}
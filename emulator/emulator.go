@@ -0,0 +1,362 @@
+// Package emulator serves the wire formats of the unofficial Google and Microsoft endpoints used by go_translate
+// with deterministic fake translations, so applications and tests can run without network access.
+package emulator
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+)
+
+// Paths of the emulated endpoints. The query strings of the real endpoints are accepted but not required.
+const (
+	PathGoogleHtml       = "/v1/translateHtml"           // TypeHtml, JSON+protobuf POST
+	PathGooglePa         = "/v1/translate"               // TypePaGtx
+	PathGoogleGtx        = "/translate_a/single"         // TypeClientGtx
+	PathGoogleDict       = "/translate_a/t"              // TypeClientDictChromeEx
+	PathGoogleDictionary = "/v1/dictionaryExtensionData" // TypeDictionary
+	PathMicrosoftAuth    = "/translate/auth"             // Edge authorization token
+	PathMicrosoftEdge    = "/translate"                  // TypeEdge
+	PathSmartLink        = "/dotrans_20160909.php"       // TypeSmartLink
+)
+
+// Failure is a kind of failure the emulator can inject instead of answering a request.
+type Failure string
+
+const (
+	// FailRateLimit answers 429 Too Many Requests with a Retry-After header.
+	FailRateLimit Failure = "rate-limit"
+
+	// FailServerError answers 503 Service Unavailable.
+	FailServerError Failure = "server-error"
+
+	// FailCaptcha answers 200 with the HTML "unusual traffic" page Google serves to blocked clients.
+	FailCaptcha Failure = "captcha"
+)
+
+// TokenLifetime is the validity of the Edge authorization tokens issued by the emulator.
+const TokenLifetime = 10 * time.Minute
+
+// captchaPage is a trimmed-down copy of the page Google serves instead of a translation when a client is blocked.
+const captchaPage = `<html><head><title>https://translate.google.com/</title></head><body>` +
+	`<div id="captcha-form">Our systems have detected unusual traffic from your computer network. ` +
+	`This page checks to see if it's really you sending the requests, and not a robot.</div></body></html>`
+
+// Options configures an Emulator.
+type Options struct {
+	// Translate computes the fake translation of one line of text (default "[target] text").
+	Translate func(text, target string) string
+
+	// SourceLanguage is the language reported as detected (default "en").
+	SourceLanguage string
+
+	// Latency delays every response.
+	Latency time.Duration
+
+	// FailureRate is the fraction of requests, between 0 and 1, answered with Failure instead of a translation.
+	FailureRate float64
+
+	// Failure is the failure injected according to FailureRate (default FailRateLimit).
+	Failure Failure
+
+	// Seed seeds the random source deciding which requests fail, so runs are reproducible.
+	Seed int64
+}
+
+// Emulator is an http.Handler emulating the Google and Microsoft endpoints. It is safe for concurrent use.
+type Emulator struct {
+	opts Options
+
+	mu       sync.Mutex
+	rand     *rand.Rand
+	failures []Failure
+	tokens   map[string]time.Time // Expiry of the issued tokens, removed once expired
+	requests int
+	now      func() time.Time
+}
+
+// New creates an Emulator.
+func New(opts Options) *Emulator {
+	if opts.Translate == nil {
+		opts.Translate = func(text, target string) string {
+			return "[" + target + "] " + text
+		}
+	}
+	if opts.SourceLanguage == "" {
+		opts.SourceLanguage = "en"
+	}
+	if opts.Failure == "" {
+		opts.Failure = FailRateLimit
+	}
+	return &Emulator{
+		opts:   opts,
+		rand:   rand.New(rand.NewSource(opts.Seed)),
+		tokens: map[string]time.Time{},
+		now:    time.Now,
+	}
+}
+
+// FailNext makes the next n requests fail with failure, before FailureRate is considered.
+func (e *Emulator) FailNext(failure Failure, n int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for i := 0; i < n; i++ {
+		e.failures = append(e.failures, failure)
+	}
+}
+
+// Requests returns the number of requests received so far.
+func (e *Emulator) Requests() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.requests
+}
+
+// ServeHTTP implements http.Handler.
+func (e *Emulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if e.opts.Latency > 0 {
+		select {
+		case <-time.After(e.opts.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if failure, ok := e.nextFailure(); ok {
+		writeFailure(w, failure)
+		return
+	}
+	switch r.URL.Path {
+	case PathGoogleHtml:
+		e.serveGoogleHtml(w, r)
+	case PathGooglePa:
+		e.serveGooglePa(w, r)
+	case PathGoogleGtx:
+		e.serveGoogleGtx(w, r)
+	case PathGoogleDict:
+		e.serveGoogleDict(w, r)
+	case PathGoogleDictionary:
+		e.serveGoogleDictionary(w, r)
+	case PathMicrosoftAuth:
+		e.serveMicrosoftAuth(w, r)
+	case PathMicrosoftEdge:
+		e.serveMicrosoftEdge(w, r)
+	case PathSmartLink:
+		e.serveSmartLink(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// nextFailure counts the request and returns the failure to inject, if any.
+func (e *Emulator) nextFailure() (Failure, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.requests++
+	if len(e.failures) > 0 {
+		failure := e.failures[0]
+		e.failures = e.failures[1:]
+		return failure, true
+	}
+	if e.opts.FailureRate > 0 && e.rand.Float64() < e.opts.FailureRate {
+		return e.opts.Failure, true
+	}
+	return "", false
+}
+
+func writeFailure(w http.ResponseWriter, failure Failure) {
+	switch failure {
+	case FailCaptcha:
+		w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		io.WriteString(w, captchaPage)
+	case FailServerError:
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+	default:
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+	}
+}
+
+// translateLines translates every line of a newline-joined text, as the real endpoints do.
+func (e *Emulator) translateLines(text, target string) []string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = e.opts.Translate(line, target)
+	}
+	return lines
+}
+
+// serveGoogleHtml answers [[["text",...],"auto","vi"],"wt_lib"] with [["translated",...],["en",...]].
+func (e *Emulator) serveGoogleHtml(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var payload []json.RawMessage
+	var request []json.RawMessage
+	var texts []string
+	var target string
+	body, _ := io.ReadAll(r.Body)
+	if json.Unmarshal(body, &payload) != nil || len(payload) == 0 ||
+		json.Unmarshal(payload[0], &request) != nil || len(request) < 3 ||
+		json.Unmarshal(request[0], &texts) != nil || json.Unmarshal(request[2], &target) != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	translated := make([]string, len(texts))
+	sources := make([]string, len(texts))
+	for i, text := range texts {
+		translated[i] = e.opts.Translate(text, target)
+		sources[i] = e.opts.SourceLanguage
+	}
+	writeJSON(w, []any{translated, sources})
+}
+
+// serveGooglePa answers {"sourceLanguage":"en","translation":"..."}.
+func (e *Emulator) serveGooglePa(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	lines := e.translateLines(query.Get("query.text"), query.Get("query.target_language"))
+	writeJSON(w, map[string]string{"sourceLanguage": e.opts.SourceLanguage, "translation": strings.Join(lines, "\n")})
+}
+
+// serveGoogleGtx answers the translate_a/single array with one sentence per line.
+func (e *Emulator) serveGoogleGtx(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	sourceLines := strings.Split(query.Get("q"), "\n")
+	lines := e.translateLines(query.Get("q"), query.Get("tl"))
+	sentences := make([]any, len(lines))
+	for i, line := range lines {
+		source := sourceLines[i]
+		if i < len(lines)-1 {
+			line += "\n"
+			source += "\n"
+		}
+		sentences[i] = []any{line, source, nil, nil, 10}
+	}
+	lang := e.opts.SourceLanguage
+	writeJSON(w, []any{sentences, nil, lang, nil, nil, nil, 1, []any{}, []any{[]string{lang}, nil, []int{1}, []string{lang}}})
+}
+
+// serveGoogleDict answers the translate_a/t array [["translated","en"]].
+func (e *Emulator) serveGoogleDict(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	lines := e.translateLines(query.Get("q"), query.Get("tl"))
+	writeJSON(w, [][]string{{strings.Join(lines, "\n"), e.opts.SourceLanguage}})
+}
+
+// serveGoogleDictionary answers dictionaryExtensionData with a translateResponse object.
+func (e *Emulator) serveGoogleDictionary(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	target := query.Get("language")
+	lines := e.translateLines(query.Get("term"), target)
+	writeJSON(w, map[string]any{
+		"status": 200,
+		"translateResponse": map[string]string{
+			"detectedSourceLanguage": e.opts.SourceLanguage,
+			"outputLanguage":         target,
+			"sourceText":             query.Get("term"),
+			"translateText":          strings.Join(lines, "\n"),
+		},
+	})
+}
+
+// serveMicrosoftAuth issues an unsigned JWT valid for TokenLifetime, as plain text.
+func (e *Emulator) serveMicrosoftAuth(w http.ResponseWriter, r *http.Request) {
+	now := e.now()
+	expiry := now.Add(TokenLifetime)
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	claims := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"region":"global","iss":"urn:ms.cognitiveservices","aud":"urn:ms.microsoftTranslator","exp":%d}`, expiry.Unix())))
+	token := header + "." + claims + "." + base64.RawURLEncoding.EncodeToString([]byte("emulator"))
+	e.mu.Lock()
+	for issued, issuedExpiry := range e.tokens {
+		if now.After(issuedExpiry) {
+			delete(e.tokens, issued)
+		}
+	}
+	e.tokens[token] = expiry
+	e.mu.Unlock()
+	w.Header().Set("Content-Type", "text/plain")
+	io.WriteString(w, token)
+}
+
+// serveMicrosoftEdge answers [{"translations":[{"text":"...","to":"vi"}]},...] for a valid token.
+func (e *Emulator) serveMicrosoftEdge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	e.mu.Lock()
+	expiry, ok := e.tokens[token]
+	expired := ok && e.now().After(expiry)
+	if expired {
+		delete(e.tokens, token)
+	}
+	e.mu.Unlock()
+	if !ok || expired {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		io.WriteString(w, `{"error":{"code":401000,"message":"The request is not authorized because credentials are missing or invalid."}}`)
+		return
+	}
+	var items []struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	target := r.URL.Query().Get("to")
+	entries := make([]any, len(items))
+	for i, item := range items {
+		entries[i] = map[string]any{"translations": []map[string]string{{"text": e.opts.Translate(item.Text, target), "to": target}}}
+	}
+	writeJSON(w, entries)
+}
+
+// serveSmartLink answers the smart-link PHP endpoint: the translated lines as an unquoted, ASCII-escaped JSON string.
+func (e *Emulator) serveSmartLink(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	dir := strings.SplitN(r.Form.Get("dir"), "/", 2)
+	if len(dir) != 2 {
+		http.Error(w, "invalid dir", http.StatusBadRequest)
+		return
+	}
+	lines := e.translateLines(r.Form.Get("text"), dir[1])
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	io.WriteString(w, escapeASCII(strings.Join(lines, "\n")))
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(v)
+}
+
+// escapeASCII escapes text like a JSON string body, with every non-ASCII character written as \uXXXX.
+func escapeASCII(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r < 0x20 || r > 0x7e:
+			for _, unit := range utf16.Encode([]rune{r}) {
+				fmt.Fprintf(&b, `\u%04x`, unit)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
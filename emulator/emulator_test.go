@@ -0,0 +1,123 @@
+package emulator_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	go_translate "github.com/dinhcanh303/go_translate"
+	"github.com/dinhcanh303/go_translate/emulator"
+	"github.com/dinhcanh303/go_translate/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// emulatedOptions points every Google and Microsoft endpoint of opts at the emulator.
+func emulatedOptions(t *testing.T, baseURL string, opts *go_translate.TranslateOptions) *go_translate.TranslateOptions {
+	rebase := func(endpoint string) string {
+		u, err := url.Parse(endpoint)
+		require.Nil(t, err)
+		return baseURL + u.RequestURI()
+	}
+	opts.GoogleEndpoints = map[go_translate.GoogleAPIType]string{}
+	for apiType, endpoint := range go_translate.GoogleUrls {
+		opts.GoogleEndpoints[apiType] = rebase(endpoint)
+	}
+	opts.MicrosoftEndpoints = map[go_translate.MicrosoftAPIType]string{}
+	for apiType, endpoint := range go_translate.MicrosoftUrls {
+		opts.MicrosoftEndpoints[apiType] = rebase(endpoint)
+	}
+	opts.MicrosoftAuthURL = rebase(go_translate.AuthEdgeUrl)
+	return opts
+}
+
+func TestEmulatorWireFormats(t *testing.T) {
+	server := httptest.NewServer(emulator.New(emulator.Options{}))
+	defer server.Close()
+
+	ctx := context.Background()
+	input := []string{"Hello", "Say \"hi\" to Zoë"}
+	expected := []string{"[vi] Hello", "[vi] Say \"hi\" to Zoë"}
+	tcs := map[string]*go_translate.TranslateOptions{}
+	for _, apiType := range go_translate.GoogleAPITypeSupport {
+		tcs[string(apiType)] = &go_translate.TranslateOptions{Provider: go_translate.ProviderGoogle, GoogleAPIType: apiType, AddToken: true}
+	}
+	tcs["edge"] = &go_translate.TranslateOptions{Provider: go_translate.ProviderMicrosoft, MicrosoftAPIType: go_translate.TypeEdge}
+	tcs["smart-link"] = &go_translate.TranslateOptions{Provider: go_translate.ProviderMicrosoft, MicrosoftAPIType: go_translate.TypeSmartLink}
+
+	for scenario, opts := range tcs {
+		opts := opts
+		t.Run(scenario, func(t *testing.T) {
+			texts := input
+			want := expected
+			// The HTML endpoint body is built without escaping, so quotes cannot be sent through it.
+			if opts.GoogleAPIType == go_translate.TypeHtml {
+				texts = []string{"Hello", "Zoë"}
+				want = []string{"[vi] Hello", "[vi] Zoë"}
+			}
+			translator, err := go_translate.NewTranslator(emulatedOptions(t, server.URL, opts))
+			require.Nil(t, err)
+			result, err := translator.TranslateText(ctx, texts, "vi")
+			require.Nil(t, err)
+			require.Equal(t, want, result)
+		})
+	}
+}
+
+func TestEmulatorFailures(t *testing.T) {
+	emu := emulator.New(emulator.Options{Translate: func(text, target string) string { return strings.ToUpper(text) }})
+	server := httptest.NewServer(emu)
+	defer server.Close()
+	ctx := context.Background()
+
+	opts := emulatedOptions(t, server.URL, &go_translate.TranslateOptions{
+		Provider:      go_translate.ProviderGoogle,
+		GoogleAPIType: go_translate.TypePaGtx,
+		MaxRetries:    2,
+		RetryBackoff:  time.Millisecond,
+	})
+	translator, err := go_translate.NewTranslator(opts)
+	require.Nil(t, err)
+
+	emu.FailNext(emulator.FailRateLimit, 1)
+	emu.FailNext(emulator.FailServerError, 1)
+	result, err := translator.TranslateText(ctx, []string{"hello"}, "vi")
+	require.Nil(t, err)
+	require.Equal(t, []string{"HELLO"}, result)
+	require.Equal(t, 3, emu.Requests())
+
+	emu.FailNext(emulator.FailCaptcha, 1)
+	_, err = translator.TranslateText(ctx, []string{"hello"}, "vi")
+	require.True(t, errors.Is(err, utils.ErrBlocked))
+
+	emu.FailNext(emulator.FailRateLimit, 1)
+	_, err = utils.DoRequest(http.DefaultClient, ctx, "GET", server.URL+emulator.PathGooglePa, nil, nil, nil)
+	var httpErr *utils.HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, http.StatusTooManyRequests, httpErr.StatusCode)
+}
+
+func TestEmulatorEdgeRequiresToken(t *testing.T) {
+	server := httptest.NewServer(emulator.New(emulator.Options{}))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+emulator.PathMicrosoftEdge+"?to=vi", "application/json", strings.NewReader(`[{"text":"Hello"}]`))
+	require.Nil(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestEmulatorLatency(t *testing.T) {
+	server := httptest.NewServer(emulator.New(emulator.Options{Latency: 20 * time.Millisecond}))
+	defer server.Close()
+
+	start := time.Now()
+	resp, err := http.Get(server.URL + emulator.PathGooglePa + "?query.text=Hello&query.target_language=vi")
+	require.Nil(t, err)
+	resp.Body.Close()
+	require.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
@@ -0,0 +1,46 @@
+package emulator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiredTokensRemoved(t *testing.T) {
+	emu := New(Options{})
+	now := time.Now()
+	emu.now = func() time.Time { return now }
+	issue := func() string {
+		w := httptest.NewRecorder()
+		emu.ServeHTTP(w, httptest.NewRequest("GET", PathMicrosoftAuth, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+	translate := func(token string) int {
+		req := httptest.NewRequest("POST", PathMicrosoftEdge+"?to=vi", strings.NewReader(`[{"text":"Hello"}]`))
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		emu.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	first := issue()
+	require.Equal(t, http.StatusOK, translate(first))
+
+	// An expired token is rejected and forgotten.
+	now = now.Add(TokenLifetime + time.Second)
+	require.Equal(t, http.StatusUnauthorized, translate(first))
+	require.Empty(t, emu.tokens)
+
+	// Issuing a token forgets the expired ones.
+	second := issue()
+	now = now.Add(TokenLifetime + time.Second)
+	third := issue()
+	require.NotEqual(t, second, third)
+	require.Len(t, emu.tokens, 1)
+	require.Contains(t, emu.tokens, third)
+}
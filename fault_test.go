@@ -0,0 +1,120 @@
+package go_translate
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dinhcanh303/go_translate/emulator"
+	"github.com/dinhcanh303/go_translate/translatetest"
+	"github.com/dinhcanh303/go_translate/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFallbackUnderFaults(t *testing.T) {
+	server := httptest.NewServer(emulator.New(emulator.Options{}))
+	defer server.Close()
+
+	brokenGoogle := []translatetest.Fault{
+		{Kind: translatetest.FaultReset, APIType: string(TypeHtml)},
+		{Kind: translatetest.FaultStatus, APIType: string(TypeClientGtx), StatusCode: 503},
+		{Kind: translatetest.FaultTruncate, APIType: string(TypeClientDictChromeEx)},
+		{Kind: translatetest.FaultMalformedJSON, APIType: string(TypeDictionary)},
+	}
+	tcs := map[string]struct {
+		opts     *TranslateOptions
+		fallback Provider // Provider tried through a FallbackMiddleware when the first one fails
+		faults   []translatetest.Fault
+		expected []string
+		fails    bool
+		mayFail  bool // The provider is picked at random: the translation succeeds or fails with the fault status
+	}{
+		"sequential falls back to the only healthy API": {
+			opts:     &TranslateOptions{GoogleAPIType: TypeSequential},
+			faults:   brokenGoogle,
+			expected: []string{"[vi] Hello", "[vi] World"},
+		},
+		"sequential fails when every API is broken": {
+			opts:   &TranslateOptions{GoogleAPIType: TypeSequential},
+			faults: append(brokenGoogle, translatetest.Fault{Kind: translatetest.FaultTimeout, APIType: string(TypePaGtx)}),
+			fails:  true,
+		},
+		"mix falls back when the HTML API times out": {
+			opts:     &TranslateOptions{GoogleAPIType: TypeMix},
+			faults:   []translatetest.Fault{{Kind: translatetest.FaultTimeout, APIType: string(TypeHtml), Latency: time.Millisecond}},
+			expected: []string{"[vi] Hello", "[vi] World"},
+		},
+		"fallback middleware falls back to Microsoft": {
+			opts:     &TranslateOptions{Provider: ProviderGoogle, GoogleAPIType: TypePaGtx},
+			fallback: ProviderMicrosoft,
+			faults:   []translatetest.Fault{{Kind: translatetest.FaultStatus, Provider: string(ProviderGoogle), StatusCode: 429}},
+			expected: []string{"[vi] Hello", "[vi] World"},
+		},
+		"fallback middleware falls back to Google": {
+			opts:     &TranslateOptions{Provider: ProviderMicrosoft},
+			fallback: ProviderGoogle,
+			faults:   []translatetest.Fault{{Kind: translatetest.FaultReset, Provider: string(ProviderMicrosoft)}},
+			expected: []string{"[vi] Hello", "[vi] World"},
+		},
+		"mix provider answers or fails with the status of its broken provider": {
+			opts:     &TranslateOptions{Provider: ProviderMix, GoogleAPIType: TypePaGtx},
+			faults:   []translatetest.Fault{{Kind: translatetest.FaultStatus, Provider: string(ProviderGoogle), StatusCode: 503}},
+			expected: []string{"[vi] Hello", "[vi] World"},
+			mayFail:  true,
+		},
+		"retries absorb intermittent server errors": {
+			opts:     &TranslateOptions{GoogleAPIType: TypePaGtx, MaxRetries: 10, RetryBackoff: time.Millisecond},
+			faults:   []translatetest.Fault{{Kind: translatetest.FaultStatus, Probability: 0.5}, {Kind: translatetest.FaultReset, Probability: 0.2}},
+			expected: []string{"[vi] Hello", "[vi] World"},
+		},
+	}
+	ctx := context.Background()
+	for scenario, tc := range tcs {
+		tc := tc
+		t.Run(scenario, func(t *testing.T) {
+			// Faults may be drawn at random, so every scenario runs with several seeds.
+			for i := 0; i < 10; i++ {
+				opts := *tc.opts
+				opts.HTTPClient = translatetest.NewFaultInjector(nil, int64(i), tc.faults...).Client()
+				if tc.fallback != "" {
+					fallback, err := NewTranslator(emulatorOptions(t, server.URL, &TranslateOptions{Provider: tc.fallback, GoogleAPIType: TypePaGtx, HTTPClient: opts.HTTPClient}))
+					require.Nil(t, err)
+					opts.Middlewares = []Middleware{FallbackMiddleware(fallback)}
+				}
+				translator, err := NewTranslator(emulatorOptions(t, server.URL, &opts))
+				require.Nil(t, err)
+				ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+				result, err := translator.TranslateText(ctx, []string{"Hello", "World"}, "vi")
+				cancel()
+				require.NotErrorIs(t, err, context.DeadlineExceeded)
+				if tc.fails {
+					require.NotNil(t, err)
+					continue
+				}
+				if tc.mayFail && err != nil {
+					var httpErr *utils.HTTPError
+					require.ErrorAs(t, err, &httpErr)
+					require.Equal(t, 503, httpErr.StatusCode)
+					continue
+				}
+				require.Nil(t, err)
+				require.Equal(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestFaultLatencyHonoursDeadline(t *testing.T) {
+	server := httptest.NewServer(emulator.New(emulator.Options{}))
+	defer server.Close()
+
+	injector := translatetest.NewFaultInjector(nil, 1, translatetest.Fault{Kind: translatetest.FaultLatency, Latency: time.Second})
+	translator, err := NewTranslator(emulatorOptions(t, server.URL, &TranslateOptions{GoogleAPIType: TypePaGtx, HTTPClient: injector.Client()}))
+	require.Nil(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = translator.TranslateText(ctx, []string{"Hello"}, "vi")
+	require.NotNil(t, err)
+	require.Equal(t, 1, injector.Injected(translatetest.FaultLatency))
+}
@@ -0,0 +1,58 @@
+package go_translate
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fixtureRoute answers one path of a stand-in server with a file from testdata.
+type fixtureRoute struct {
+	fixture string                // File name in testdata
+	check   func(r *http.Request) // Optional assertions on the incoming request, run by the server: use assert, not require
+}
+
+// newFixtureServer starts a stand-in server that serves testdata fixtures per path.
+func newFixtureServer(t *testing.T, routes map[string]fixtureRoute) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	for path, route := range routes {
+		body, err := os.ReadFile(filepath.Join("testdata", route.fixture))
+		require.Nil(t, err)
+		route := route
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			if route.check != nil {
+				route.check(r)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(body)
+		})
+	}
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// emulatorOptions points every Google and Microsoft endpoint of opts at an emulator served at baseURL.
+func emulatorOptions(t *testing.T, baseURL string, opts *TranslateOptions) *TranslateOptions {
+	rebase := func(endpoint string) string {
+		u, err := url.Parse(endpoint)
+		require.Nil(t, err)
+		return baseURL + u.RequestURI()
+	}
+	opts.GoogleEndpoints = map[GoogleAPIType]string{}
+	for apiType, endpoint := range GoogleUrls {
+		opts.GoogleEndpoints[apiType] = rebase(endpoint)
+	}
+	opts.MicrosoftEndpoints = map[MicrosoftAPIType]string{}
+	for apiType, endpoint := range MicrosoftUrls {
+		opts.MicrosoftEndpoints[apiType] = rebase(endpoint)
+	}
+	opts.MicrosoftAuthURL = rebase(AuthEdgeUrl)
+	return opts
+}
@@ -0,0 +1,421 @@
+package go_translate
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dinhcanh303/go_translate/utils"
+)
+
+// GoogleCloudVersion selects the Cloud Translation API version.
+type GoogleCloudVersion string
+
+const (
+	// GoogleCloudV2 uses Cloud Translation Basic (v2), authenticated with an API key.
+	GoogleCloudV2 GoogleCloudVersion = "v2"
+
+	// GoogleCloudV3 uses Cloud Translation Advanced (v3), authenticated with a service account or access token.
+	GoogleCloudV3 GoogleCloudVersion = "v3"
+)
+
+const (
+	// GoogleCloudBaseUrl is the default endpoint of the Cloud Translation API.
+	GoogleCloudBaseUrl = "https://translation.googleapis.com"
+
+	// GoogleCloudTokenUrl is the default OAuth2 token endpoint used with service accounts.
+	GoogleCloudTokenUrl = "https://oauth2.googleapis.com/token"
+
+	googleCloudScope = "https://www.googleapis.com/auth/cloud-translation"
+
+	googleCloudV2MaxItems = 128
+	googleCloudV3MaxItems = 1024
+	googleCloudMaxChars   = 30000
+)
+
+// GoogleCloudOptions configures ProviderGoogleCloud. Set it as TranslateOptions.ProviderOptions.
+type GoogleCloudOptions struct {
+	// Version selects the API version; it defaults to v3 when service account credentials or an access token are set, v2 otherwise.
+	Version GoogleCloudVersion
+
+	// APIKey authenticates v2 requests.
+	APIKey string
+
+	// ServiceAccountJSON is the content of a service account key file, used to sign v3 access tokens locally.
+	ServiceAccountJSON []byte
+
+	// AccessToken is a ready-made OAuth2 token for v3, used instead of ServiceAccountJSON.
+	AccessToken string
+
+	// ProjectID is the v3 project; it defaults to the project of the service account.
+	ProjectID string
+
+	// Location is the v3 location (default "global"). Glossaries and custom models require a regional location.
+	Location string
+
+	// Model selects the translation model: "nmt"/"base" for v2, a model ID (e.g., "general/nmt") or full resource name for v3.
+	Model string
+
+	// GlossaryID is the v3 glossary ID or full resource name. Glossaries require a source language.
+	GlossaryID string
+
+	// Format is the input format, "text" (default) or "html".
+	Format string
+
+	// BaseURL overrides the API endpoint (e.g., for a regional endpoint or a test server).
+	BaseURL string
+
+	// TokenURL overrides the OAuth2 token endpoint of the service account.
+	TokenURL string
+}
+
+// ProviderName implements ProviderOptions.
+func (GoogleCloudOptions) ProviderName() Provider {
+	return ProviderGoogleCloud
+}
+
+// serviceAccount holds the fields of a Google service account key file used for signing tokens.
+type serviceAccount struct {
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// GoogleCloudTranslateService is a Translator and Detector for the official Google Cloud Translation API.
+type GoogleCloudTranslateService struct {
+	client  *http.Client      // HTTP client used for making API requests
+	opts    *TranslateOptions // Options for configuring the translation service
+	cloud   GoogleCloudOptions
+	account *serviceAccount
+	key     *rsa.PrivateKey
+
+	mu          sync.Mutex // guards token and tokenExpiry
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewGoogleCloudTranslateService validates the GoogleCloudOptions carried by opts and creates the service.
+func NewGoogleCloudTranslateService(client *http.Client, opts *TranslateOptions) (*GoogleCloudTranslateService, error) {
+	cloud, _ := GetProviderOptions[GoogleCloudOptions](opts)
+	s := &GoogleCloudTranslateService{client: client, opts: opts}
+	if len(cloud.ServiceAccountJSON) > 0 {
+		var account serviceAccount
+		if err := json.Unmarshal(cloud.ServiceAccountJSON, &account); err != nil {
+			return nil, fmt.Errorf("invalid service account JSON: %w", err)
+		}
+		key, err := utils.ParseRSAPrivateKey([]byte(account.PrivateKey))
+		if err != nil {
+			return nil, err
+		}
+		if cloud.ProjectID == "" {
+			cloud.ProjectID = account.ProjectID
+		}
+		if cloud.TokenURL == "" {
+			cloud.TokenURL = account.TokenURI
+		}
+		s.account, s.key = &account, key
+	}
+	if cloud.Version == "" {
+		cloud.Version = GoogleCloudV2
+		if s.account != nil || cloud.AccessToken != "" {
+			cloud.Version = GoogleCloudV3
+		}
+	}
+	if cloud.BaseURL == "" {
+		cloud.BaseURL = GoogleCloudBaseUrl
+	}
+	cloud.BaseURL = strings.TrimRight(cloud.BaseURL, "/")
+	if cloud.TokenURL == "" {
+		cloud.TokenURL = GoogleCloudTokenUrl
+	}
+	if cloud.Location == "" {
+		cloud.Location = "global"
+	}
+	if cloud.Format == "" {
+		cloud.Format = "text"
+	}
+	switch cloud.Version {
+	case GoogleCloudV2:
+		if cloud.APIKey == "" {
+			return nil, errors.New("google cloud v2 requires an API key")
+		}
+	case GoogleCloudV3:
+		if s.account == nil && cloud.AccessToken == "" {
+			return nil, errors.New("google cloud v3 requires service account credentials or an access token")
+		}
+		if cloud.ProjectID == "" {
+			return nil, errors.New("google cloud v3 requires a project ID")
+		}
+	default:
+		return nil, errors.New("unsupported google cloud version: " + string(cloud.Version))
+	}
+	s.cloud = cloud
+	return s, nil
+}
+
+// Capabilities reports the features supported by the Cloud Translation API.
+func (g *GoogleCloudTranslateService) Capabilities() Capabilities {
+	return Capabilities{Batch: true, Detection: true, HTML: true, Glossary: g.cloud.Version == GoogleCloudV3}
+}
+
+// TranslateText translates the texts with Cloud Translation, splitting them into requests within the API limits.
+// The optional detected language code is sent as the source language; otherwise the API detects it.
+func (g *GoogleCloudTranslateService) TranslateText(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+	source := sourceLanguage(detectedLangCode)
+	maxItems := googleCloudV2MaxItems
+	if g.cloud.Version == GoogleCloudV3 {
+		maxItems = googleCloudV3MaxItems
+	}
+	results := make([]string, 0, len(texts))
+	for _, chunk := range chunkTexts(texts, maxItems, googleCloudMaxChars) {
+		var translated []string
+		var err error
+		if g.cloud.Version == GoogleCloudV3 {
+			translated, err = g.translateV3(ctx, texts[chunk.start:chunk.end], target, source)
+		} else {
+			translated, err = g.translateV2(ctx, texts[chunk.start:chunk.end], target, source)
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, translated...)
+	}
+	return results, nil
+}
+
+// DetectLanguage returns the most likely language code of every text, splitting them into requests within the API limits.
+func (g *GoogleCloudTranslateService) DetectLanguage(ctx context.Context, texts []string) ([]string, error) {
+	if g.cloud.Version == GoogleCloudV3 {
+		return g.detectV3(ctx, texts)
+	}
+	languages := make([]string, 0, len(texts))
+	for _, chunk := range chunkTexts(texts, googleCloudV2MaxItems, googleCloudMaxChars) {
+		detected, err := g.detectV2(ctx, texts[chunk.start:chunk.end])
+		if err != nil {
+			return nil, err
+		}
+		languages = append(languages, detected...)
+	}
+	return languages, nil
+}
+
+func (g *GoogleCloudTranslateService) translateV2(ctx context.Context, texts []string, target, source string) ([]string, error) {
+	payload := map[string]any{"q": texts, "target": target, "format": g.cloud.Format}
+	if source != "" {
+		payload["source"] = source
+	}
+	if g.cloud.Model != "" {
+		payload["model"] = g.cloud.Model
+	}
+	var result struct {
+		Data struct {
+			Translations []struct {
+				TranslatedText string `json:"translatedText"`
+			} `json:"translations"`
+		} `json:"data"`
+	}
+	if err := g.callV2(ctx, "/language/translate/v2", payload, &result); err != nil {
+		return nil, err
+	}
+	translated := make([]string, 0, len(result.Data.Translations))
+	for _, t := range result.Data.Translations {
+		translated = append(translated, t.TranslatedText)
+	}
+	return checkTranslationCount(texts, translated)
+}
+
+func (g *GoogleCloudTranslateService) detectV2(ctx context.Context, texts []string) ([]string, error) {
+	var result struct {
+		Data struct {
+			Detections [][]struct {
+				Language string `json:"language"`
+			} `json:"detections"`
+		} `json:"data"`
+	}
+	if err := g.callV2(ctx, "/language/translate/v2/detect", map[string]any{"q": texts}, &result); err != nil {
+		return nil, err
+	}
+	languages := make([]string, 0, len(result.Data.Detections))
+	for _, detection := range result.Data.Detections {
+		language := ""
+		if len(detection) > 0 {
+			language = detection[0].Language
+		}
+		languages = append(languages, language)
+	}
+	return checkTranslationCount(texts, languages)
+}
+
+func (g *GoogleCloudTranslateService) translateV3(ctx context.Context, texts []string, target, source string) ([]string, error) {
+	parent := g.parent()
+	payload := map[string]any{
+		"contents":           texts,
+		"targetLanguageCode": target,
+		"mimeType":           "text/plain",
+	}
+	if g.cloud.Format == "html" {
+		payload["mimeType"] = "text/html"
+	}
+	if source != "" {
+		payload["sourceLanguageCode"] = source
+	}
+	if g.cloud.Model != "" {
+		payload["model"] = g.resourceName("models", g.cloud.Model)
+	}
+	if g.cloud.GlossaryID != "" {
+		if source == "" {
+			return nil, errors.New("google cloud glossaries require a source language")
+		}
+		payload["glossaryConfig"] = map[string]string{"glossary": g.resourceName("glossaries", g.cloud.GlossaryID)}
+	}
+	type translation struct {
+		TranslatedText string `json:"translatedText"`
+	}
+	var result struct {
+		Translations         []translation `json:"translations"`
+		GlossaryTranslations []translation `json:"glossaryTranslations"`
+	}
+	if err := g.callV3(ctx, parent+":translateText", payload, &result); err != nil {
+		return nil, err
+	}
+	translations := result.Translations
+	if len(result.GlossaryTranslations) > 0 {
+		translations = result.GlossaryTranslations
+	}
+	translated := make([]string, 0, len(translations))
+	for _, t := range translations {
+		translated = append(translated, t.TranslatedText)
+	}
+	return checkTranslationCount(texts, translated)
+}
+
+func (g *GoogleCloudTranslateService) detectV3(ctx context.Context, texts []string) ([]string, error) {
+	languages := make([]string, 0, len(texts))
+	for _, text := range texts {
+		var result struct {
+			Languages []struct {
+				LanguageCode string `json:"languageCode"`
+			} `json:"languages"`
+		}
+		if err := g.callV3(ctx, g.parent()+":detectLanguage", map[string]any{"content": text}, &result); err != nil {
+			return nil, err
+		}
+		language := ""
+		if len(result.Languages) > 0 {
+			language = result.Languages[0].LanguageCode
+		}
+		languages = append(languages, language)
+	}
+	return languages, nil
+}
+
+func (g *GoogleCloudTranslateService) parent() string {
+	return "projects/" + g.cloud.ProjectID + "/locations/" + g.cloud.Location
+}
+
+// resourceName expands a short ID into a full resource name under the configured project and location.
+func (g *GoogleCloudTranslateService) resourceName(collection, id string) string {
+	if strings.HasPrefix(id, "projects/") {
+		return id
+	}
+	return g.parent() + "/" + collection + "/" + id
+}
+
+func (g *GoogleCloudTranslateService) callV2(ctx context.Context, path string, payload any, out any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{"Content-Type": "application/json"}
+	params := url.Values{"key": {g.cloud.APIKey}}
+	if err := doRequestJSON(ctx, g.client, g.opts, "POST", g.cloud.BaseURL+path, headers, params, body, out); err != nil {
+		return providerError(ProviderGoogleCloud, err)
+	}
+	return nil
+}
+
+func (g *GoogleCloudTranslateService) callV3(ctx context.Context, resource string, payload any, out any) error {
+	token, err := g.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{
+		"Content-Type":  "application/json",
+		"Authorization": "Bearer " + token,
+	}
+	if err := doRequestJSON(ctx, g.client, g.opts, "POST", g.cloud.BaseURL+"/v3/"+resource, headers, nil, body, out); err != nil {
+		return providerError(ProviderGoogleCloud, err)
+	}
+	return nil
+}
+
+// accessToken returns the configured access token, or a cached token obtained by signing a JWT with the service account.
+func (g *GoogleCloudTranslateService) accessToken(ctx context.Context) (string, error) {
+	if g.cloud.AccessToken != "" {
+		return g.cloud.AccessToken, nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.token != "" && time.Now().Before(g.tokenExpiry) {
+		return g.token, nil
+	}
+	now := time.Now()
+	assertion, err := utils.SignJWTRS256(g.key, g.account.PrivateKeyID, map[string]any{
+		"iss":   g.account.ClientEmail,
+		"scope": googleCloudScope,
+		"aud":   g.cloud.TokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	headers := map[string]string{"Content-Type": "application/x-www-form-urlencoded"}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := doRequestJSON(ctx, g.client, g.opts, "POST", g.cloud.TokenURL, headers, nil, []byte(form.Encode()), &token); err != nil {
+		return "", fmt.Errorf("google cloud token exchange failed: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("google cloud token exchange returned no access token")
+	}
+	// Refresh a minute early so that in-flight requests never carry an expired token.
+	g.token = token.AccessToken
+	g.tokenExpiry = now.Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return g.token, nil
+}
+
+// sourceLanguage returns the optional detected language code passed to TranslateText, ignoring "auto".
+func sourceLanguage(detectedLangCode []string) string {
+	if len(detectedLangCode) == 0 || detectedLangCode[0] == "auto" {
+		return ""
+	}
+	return detectedLangCode[0]
+}
+
+// checkTranslationCount makes sure a provider returned exactly one result per input text.
+func checkTranslationCount(texts, translated []string) ([]string, error) {
+	if len(translated) != len(texts) {
+		return nil, fmt.Errorf("unexpected response: expected %d results, got %d", len(texts), len(translated))
+	}
+	return translated, nil
+}
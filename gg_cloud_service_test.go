@@ -0,0 +1,171 @@
+package go_translate
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newServiceAccountJSON(t *testing.T, key *rsa.PrivateKey, tokenURI string) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.Nil(t, err)
+	account, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "my-project",
+		"private_key_id": "key-1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"client_email":   "translator@my-project.iam.gserviceaccount.com",
+		"token_uri":      tokenURI,
+	})
+	require.Nil(t, err)
+	return account
+}
+
+func TestGoogleCloudV2(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first call is throttled to exercise retries.
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		require.Equal(t, "test-key", r.URL.Query().Get("key"))
+		var payload struct {
+			Q      []string `json:"q"`
+			Target string   `json:"target"`
+			Source string   `json:"source"`
+		}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&payload))
+		switch r.URL.Path {
+		case "/language/translate/v2":
+			var translations []map[string]string
+			for _, q := range payload.Q {
+				translations = append(translations, map[string]string{"translatedText": payload.Target + ":" + q})
+			}
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"translations": translations}})
+		case "/language/translate/v2/detect":
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"detections": [][]map[string]any{{{"language": "en", "confidence": 1}}}}})
+		}
+	}))
+	defer server.Close()
+
+	translator, err := NewTranslator(&TranslateOptions{
+		Provider:        ProviderGoogleCloud,
+		MaxRetries:      1,
+		RetryBackoff:    time.Millisecond,
+		ProviderOptions: GoogleCloudOptions{APIKey: "test-key", BaseURL: server.URL},
+	})
+	require.Nil(t, err)
+	result, err := translator.TranslateText(context.Background(), []string{"Hello", "World"}, "vi")
+	require.Nil(t, err)
+	require.Equal(t, []string{"vi:Hello", "vi:World"}, result)
+
+	languages, err := translator.(Detector).DetectLanguage(context.Background(), []string{"Hello"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"en"}, languages)
+	require.False(t, CapabilitiesOf(translator).Glossary)
+}
+
+func TestGoogleCloudV2DetectChunks(t *testing.T) {
+	var sizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Q []string `json:"q"`
+		}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&payload))
+		sizes = append(sizes, len(payload.Q))
+		detections := make([][]map[string]any, len(payload.Q))
+		for i := range detections {
+			detections[i] = []map[string]any{{"language": "en", "confidence": 1}}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"detections": detections}})
+	}))
+	defer server.Close()
+
+	translator, err := NewTranslator(&TranslateOptions{
+		Provider:        ProviderGoogleCloud,
+		ProviderOptions: GoogleCloudOptions{APIKey: "test-key", BaseURL: server.URL},
+	})
+	require.Nil(t, err)
+	texts := make([]string, 300)
+	for i := range texts {
+		texts[i] = "Hello"
+	}
+	languages, err := translator.(Detector).DetectLanguage(context.Background(), texts)
+	require.Nil(t, err)
+	require.Len(t, languages, 300)
+	require.Equal(t, []int{googleCloudV2MaxItems, googleCloudV2MaxItems, 44}, sizes)
+}
+
+func TestGoogleCloudV3(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	var tokenCalls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenCalls, 1)
+		require.Nil(t, r.ParseForm())
+		parts := strings.Split(r.PostForm.Get("assertion"), ".")
+		require.Len(t, parts, 3)
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.Nil(t, err)
+		hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		require.Nil(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hash[:], signature))
+		json.NewEncoder(w).Encode(map[string]any{"access_token": "signed-token", "expires_in": 3600})
+	})
+	mux.HandleFunc("/v3/projects/my-project/locations/us-central1:translateText", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer signed-token", r.Header.Get("Authorization"))
+		var payload map[string]any
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&payload))
+		require.Equal(t, "projects/my-project/locations/us-central1/models/general/nmt", payload["model"])
+		require.Equal(t, map[string]any{"glossary": "projects/my-project/locations/us-central1/glossaries/product-terms"}, payload["glossaryConfig"])
+		json.NewEncoder(w).Encode(map[string]any{
+			"translations":         []map[string]string{{"translatedText": "Xin chào Go Translate"}},
+			"glossaryTranslations": []map[string]string{{"translatedText": "Xin chào GoTranslate"}},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	translator, err := NewTranslator(&TranslateOptions{
+		Provider: ProviderGoogleCloud,
+		ProviderOptions: GoogleCloudOptions{
+			ServiceAccountJSON: newServiceAccountJSON(t, key, server.URL+"/token"),
+			Location:           "us-central1",
+			Model:              "general/nmt",
+			GlossaryID:         "product-terms",
+			BaseURL:            server.URL,
+		},
+	})
+	require.Nil(t, err)
+	for i := 0; i < 2; i++ {
+		result, err := translator.TranslateText(context.Background(), []string{"Hello GoTranslate"}, "vi", "en")
+		require.Nil(t, err)
+		require.Equal(t, []string{"Xin chào GoTranslate"}, result)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&tokenCalls))
+
+	_, err = translator.TranslateText(context.Background(), []string{"Hello"}, "vi")
+	require.NotNil(t, err, "glossaries require a source language")
+}
+
+func TestGoogleCloudOptionsValidation(t *testing.T) {
+	_, err := NewTranslator(&TranslateOptions{Provider: ProviderGoogleCloud})
+	require.NotNil(t, err)
+	_, err = NewTranslator(&TranslateOptions{Provider: ProviderGoogleCloud, ProviderOptions: GoogleCloudOptions{AccessToken: "token"}})
+	require.NotNil(t, err, "v3 requires a project")
+}
@@ -5,27 +5,102 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
+	"github.com/dinhcanh303/go_translate/metrics"
+	"github.com/dinhcanh303/go_translate/tracing"
 	"github.com/dinhcanh303/go_translate/utils"
 )
 
 // GoogleTranslateService is a concrete implementation of the Translator interface for Google Translate.
 // It supports multiple API endpoints and handles requests for different Google Translate API types.
 type GoogleTranslateService struct {
-	client *http.Client      // HTTP client used for making API requests
-	opts   *TranslateOptions // Options for configuring the translation service
+	client    *http.Client               // HTTP client used for making API requests
+	opts      *TranslateOptions          // Options for configuring the translation service
+	endpoints map[GoogleAPIType]string   // Endpoint of every API type, defaults merged with opts.GoogleEndpoints
+	keys      map[GoogleAPIType]*KeyPool // API key pools of the HTML, PaGtx and Dictionary APIs
 }
 
 // NewGoogleTranslateService creates a new instance of GoogleTranslateService with the given options.
 // The HTTP client timeout is set to 5 seconds.
 func NewGoogleTranslateService(client *http.Client, opts *TranslateOptions) *GoogleTranslateService {
+	endpoints := make(map[GoogleAPIType]string, len(GoogleUrls))
+	for apiType, endpoint := range GoogleUrls {
+		endpoints[apiType] = endpoint
+	}
+	for apiType, endpoint := range opts.GoogleEndpoints {
+		endpoints[apiType] = endpoint
+	}
 	return &GoogleTranslateService{
-		client: client,
-		opts:   opts,
+		client:    client,
+		opts:      opts,
+		endpoints: endpoints,
+		keys: map[GoogleAPIType]*KeyPool{
+			TypeHtml:       newGoogleKeyPool(opts, TypeHtml, opts.GoogleAPIKeysTranslateHtml, opts.GoogleAPIKeyTranslateHtml),
+			TypePaGtx:      newGoogleKeyPool(opts, TypePaGtx, opts.GoogleAPIKeysTranslatePa, opts.GoogleAPIKeyTranslatePa),
+			TypeDictionary: newGoogleKeyPool(opts, TypeDictionary, opts.GoogleAPIKeysTranslateDic, opts.GoogleAPIKeyTranslateDic),
+		},
+	}
+}
+
+// newGoogleKeyPool creates the pool of keys of apiType, or of the single key when no pool is configured.
+// The quarantine of the keys is reported as metrics.BreakerState, labeled by key index.
+func newGoogleKeyPool(opts *TranslateOptions, apiType GoogleAPIType, keys []string, key string) *KeyPool {
+	if len(keys) == 0 {
+		keys = []string{key}
+	}
+	pool := NewKeyPool(keys, opts.APIKeyRotation, opts.APIKeyCooldown)
+	if opts.Metrics != nil {
+		pool.onChange = func(index int, quarantined bool) {
+			state := 0.0
+			if quarantined {
+				state = 1
+			}
+			opts.Metrics.Set(metrics.BreakerState, state, metrics.Labels{
+				"kind": "api_key", "name": strconv.Itoa(index), "provider": string(ProviderGoogle), "api_type": string(apiType),
+			})
+		}
+	}
+	return pool
+}
+
+// KeyStats reports the usage of the API keys of the HTML, PaGtx and Dictionary APIs.
+func (s *GoogleTranslateService) KeyStats() map[GoogleAPIType][]KeyStats {
+	stats := make(map[GoogleAPIType][]KeyStats, len(s.keys))
+	for apiType, pool := range s.keys {
+		stats[apiType] = pool.Stats()
+	}
+	return stats
+}
+
+// withKey calls call with the next key of the pool of apiType and records the outcome.
+// When the key is throttled, it is quarantined and call is tried again with the next key until the pool is exhausted;
+// the requests are marked so that RetryMiddleware does not resend them with the throttled key.
+// A single key is not rotated: the throttled request is left to RetryMiddleware.
+func (s *GoogleTranslateService) withKey(ctx context.Context, apiType GoogleAPIType, call func(ctx context.Context, key string) ([]string, error)) ([]string, error) {
+	pool := s.keys[apiType]
+	if len(pool.keys) > 1 {
+		// A throttled request is sent again with the next key instead of the same one.
+		ctx = withKeyRotation(ctx)
+	}
+	var throttled error
+	for {
+		key, err := pool.Next()
+		if err != nil {
+			if throttled != nil {
+				return nil, fmt.Errorf("%w: %w", err, throttled)
+			}
+			return nil, err
+		}
+		result, err := call(ctx, key)
+		pool.Report(key, err)
+		if !isThrottled(err) || len(pool.keys) == 1 {
+			return result, err
+		}
+		throttled = err
 	}
 }
 
@@ -36,7 +111,7 @@ func (s *GoogleTranslateService) translate(ctx context.Context, texts []string,
 	if s.opts.GoogleAPIType == TypeRandom {
 		googleApiType = utils.GetRandomValue(GoogleAPITypeSupport)
 	}
-	endpoint := GoogleUrls[googleApiType]
+	endpoint := s.endpoints[googleApiType]
 	handlers := s.getAPIHandlers()
 	handler, ok := handlers[googleApiType]
 	if !ok {
@@ -47,8 +122,13 @@ func (s *GoogleTranslateService) translate(ctx context.Context, texts []string,
 	if err == nil && translatedText != nil {
 		return translatedText, nil
 	}
-	log.Printf("[ERROR] API %s failed: %v", googleApiType, err)
-	return nil, errors.New("unable to translate text, all APIs failed")
+	if errors.Is(err, errAllAPIsFailed) {
+		return nil, err
+	}
+	if err != nil {
+		loggerOf(s.opts).WarnContext(ctx, "API failed", "provider", ProviderGoogle, "api_type", googleApiType, errorAttr(err))
+	}
+	return nil, allAPIsFailed(err)
 }
 
 // TranslateBatchText translates the provided text into the target language using the configured provider and API type.
@@ -57,82 +137,110 @@ func (s *GoogleTranslateService) TranslateText(ctx context.Context, texts []stri
 	return s.translate(ctx, texts, target)
 }
 
+// Capabilities reports the features supported by the unofficial Google endpoints.
+func (s *GoogleTranslateService) Capabilities() Capabilities {
+	return Capabilities{Batch: true, HTML: s.opts.GoogleAPIType == TypeHtml}
+}
+
 // callTranslateHTML makes a POST request to the HTML API endpoint and returns the translated text.
 func (s *GoogleTranslateService) callTranslateHTML(ctx context.Context, texts []string, target, endpoint string) ([]string, error) {
 	body := buildGoogleHTMLBody(texts, target)
-	headers := map[string]string{
-		"User-Agent":     utils.GetConditionalRandomValue(DefaultUserAgents, s.opts.CustomUserAgents, s.opts.UseRandomUserAgents),
-		"Content-Type":   "application/json+protobuf",
-		"X-Goog-API-Key": s.opts.GoogleAPIKeyTranslateHtml,
-	}
-	return s.executeAPIRequest(ctx, "POST", endpoint, headers, nil, []byte(body), utils.ExtractTranslatedTextFromHtml)
+	return s.withKey(ctx, TypeHtml, func(ctx context.Context, key string) ([]string, error) {
+		headers := requestHeaders(s.opts, string(TypeHtml), map[string]string{
+			"Content-Type":   "application/json+protobuf",
+			"X-Goog-API-Key": key,
+		})
+		return s.executeAPIRequest(ctx, "POST", endpoint, headers, nil, []byte(body), utils.ExtractTranslatedTextFromHtml)
+	})
 }
 
 // callTranslateGet makes a GET request to the Google Translate API (client-gtx or client-dict) and returns the translated text.
 func (s *GoogleTranslateService) callTranslateGet(ctx context.Context, texts []string, target, endpoint string, isGtx bool) ([]string, error) {
 	text := utils.JoinWithSeparator(texts)
-	fullURL := "https://" + utils.GetConditionalRandomValue(DefaultServiceUrls, s.opts.CustomServiceUrls, s.opts.UseRandomServiceUrls) + endpoint
+	fullURL := endpoint
+	if !isAbsoluteURL(endpoint) {
+		fullURL = "https://" + utils.GetConditionalRandomValue(DefaultServiceUrls, s.opts.CustomServiceUrls, s.opts.UseRandomServiceUrls) + endpoint
+	}
 	params := url.Values{
 		"tl": {target},
 		"q":  {text},
 	}
 	if s.opts.AddToken {
-		params.Set("tk", utils.GgTokenGenerate(text))
-	}
-	headers := map[string]string{
-		"User-Agent": utils.GetConditionalRandomValue(DefaultUserAgents, s.opts.CustomUserAgents, s.opts.UseRandomUserAgents),
+		params.Set("tk", utils.GgTokenGenerate(text, s.tkk(ctx)))
 	}
-	extractFunc := utils.ExtractTranslatedText
+	apiType, extractFunc := TypeClientDictChromeEx, utils.ExtractTranslatedText
 	if isGtx {
-		extractFunc = utils.ExtractTranslatedTextFromArray
+		apiType, extractFunc = TypeClientGtx, utils.ExtractTranslatedTextFromArray
 	}
+	headers := requestHeaders(s.opts, string(apiType), nil)
 	return s.executeAPIRequest(ctx, "GET", fullURL, headers, params, nil, extractFunc)
 }
 
-// callTranslatePa makes a GET request to the PaGtx API endpoint and returns the translated text.
-func (s *GoogleTranslateService) callTranslatePa(ctx context.Context, texts []string, target, endpoint string) ([]string, error) {
-	params := url.Values{
-		"query.target_language": {target},
-		"key":                   {s.opts.GoogleAPIKeyTranslatePa},
-		"query.text":            {utils.JoinWithSeparator(texts)},
+// tkk returns the key of the "tk" token, falling back to utils.DefaultTKK if the TKKSource fails.
+func (s *GoogleTranslateService) tkk(ctx context.Context) utils.TKK {
+	if s.opts.TKKSource == nil {
+		return utils.DefaultTKK()
 	}
-	headers := map[string]string{
-		"User-Agent": utils.GetConditionalRandomValue(DefaultUserAgents, s.opts.CustomUserAgents, s.opts.UseRandomUserAgents),
+	ctx, span := tracerOf(s.opts).Start(ctx, tracing.TokenFetch,
+		tracing.String(tracing.Provider, string(ProviderGoogle)), tracing.String(tracing.Token, "tkk"))
+	ctx = withPageFetcher(ctx, func(ctx context.Context, pageURL string) ([]byte, error) {
+		return doRequest(ctx, s.client, s.opts, "GET", pageURL, requestHeaders(s.opts, "tkk", nil), nil, nil)
+	})
+	tkk, err := s.opts.TKKSource.TKK(ctx)
+	endSpan(span, err)
+	if err != nil {
+		loggerOf(s.opts).WarnContext(ctx, "TKK source failed, using the default key", "provider", ProviderGoogle, errorAttr(err))
+		return utils.DefaultTKK()
 	}
+	return tkk
+}
 
-	return s.executeAPIRequest(ctx, "GET", endpoint, headers, params, nil, utils.ExtractTranslatedTextFromJson)
+// callTranslatePa makes a GET request to the PaGtx API endpoint and returns the translated text.
+func (s *GoogleTranslateService) callTranslatePa(ctx context.Context, texts []string, target, endpoint string) ([]string, error) {
+	headers := requestHeaders(s.opts, string(TypePaGtx), nil)
+	return s.withKey(ctx, TypePaGtx, func(ctx context.Context, key string) ([]string, error) {
+		params := url.Values{
+			"query.target_language": {target},
+			"key":                   {key},
+			"query.text":            {utils.JoinWithSeparator(texts)},
+		}
+		return s.executeAPIRequest(ctx, "GET", endpoint, headers, params, nil, utils.ExtractTranslatedTextFromJson)
+	})
 }
 
 // callTranslatePa makes a GET request to the PaGtx API endpoint and returns the translated text.
 func (s *GoogleTranslateService) callTranslateDic(ctx context.Context, texts []string, target, endpoint string) ([]string, error) {
-	params := url.Values{
-		"language": {target},
-		"key":      {s.opts.GoogleAPIKeyTranslateDic},
-		"term":     {utils.JoinWithSeparator(texts)},
-	}
-	headers := map[string]string{
-		"User-Agent": utils.GetConditionalRandomValue(DefaultUserAgents, s.opts.CustomUserAgents, s.opts.UseRandomUserAgents),
-		"x-referer":  "chrome-extension://mgijmajocgfcbeboacabfgobmjgjcoja",
-	}
-
-	return s.executeAPIRequest(ctx, "GET", endpoint, headers, params, nil, utils.ExtractTranslatedTextFromGGDic)
+	headers := requestHeaders(s.opts, string(TypeDictionary), map[string]string{
+		"x-referer": "chrome-extension://mgijmajocgfcbeboacabfgobmjgjcoja",
+	})
+	return s.withKey(ctx, TypeDictionary, func(ctx context.Context, key string) ([]string, error) {
+		params := url.Values{
+			"language": {target},
+			"key":      {key},
+			"term":     {utils.JoinWithSeparator(texts)},
+		}
+		return s.executeAPIRequest(ctx, "GET", endpoint, headers, params, nil, utils.ExtractTranslatedTextFromGGDic)
+	})
 }
 
 // callTranslateGet makes a GET request to the Google Translate API (client-gtx or client-dict) and returns the translated text.
 func (s *GoogleTranslateService) callTranslateSequential(ctx context.Context, texts []string, target string) ([]string, error) {
 	handlers := s.getAPIHandlers()
-	for apiType, endpoint := range GoogleUrls {
+	var errs []error
+	for apiType, endpoint := range s.endpoints {
 		handler, ok := handlers[apiType]
 		if !ok {
 			continue // skip unsupported apiTypes
 		}
-		translatedText, err := handler(ctx, texts, target, endpoint)
+		translatedText, err := s.callAPI(ctx, apiType, handler, texts, target, endpoint)
 		if err == nil && translatedText != nil {
 			return translatedText, nil
 		}
-		log.Printf("[ERROR] Sequential API %s failed: %v", apiType, err)
+		loggerOf(s.opts).WarnContext(ctx, "API failed, trying the next one", "provider", ProviderGoogle, "api_type", apiType, errorAttr(err))
+		metricsOf(s.opts).Add(metrics.Fallbacks, 1, metrics.Labels{"provider": string(ProviderGoogle), "api_type": string(apiType)})
+		errs = append(errs, err)
 	}
-	return nil, errors.New("unable to translate text, all APIs failed")
+	return nil, allAPIsFailed(errs...)
 }
 func (s *GoogleTranslateService) callTranslateMix(
 	ctx context.Context,
@@ -141,16 +249,17 @@ func (s *GoogleTranslateService) callTranslateMix(
 ) ([]string, error) {
 	// googleApiType := utils.GetRandomValue(GoogleAPITypeSupport)
 	googleApiType := TypeHtml
-	endpoint := GoogleUrls[googleApiType]
+	endpoint := s.endpoints[googleApiType]
 	handlers := s.getAPIHandlers()
 	handler, ok := handlers[googleApiType]
 	if !ok {
 		return nil, errors.New("unsupported Google API type: " + string(googleApiType))
 	}
-	translatedText, err := handler(ctx, texts, target, endpoint)
+	translatedText, err := s.callAPI(ctx, googleApiType, handler, texts, target, endpoint)
 	if err == nil && translatedText != nil {
 		return translatedText, nil
 	}
+	errs := []error{err}
 	var remainHandlers = make(map[GoogleAPIType]apiHandler)
 	exclude := map[GoogleAPIType]struct{}{
 		googleApiType:  {},
@@ -163,18 +272,29 @@ func (s *GoogleTranslateService) callTranslateMix(
 		}
 		remainHandlers[k] = v
 	}
-	for apiType, endpoint := range GoogleUrls {
+	for apiType, endpoint := range s.endpoints {
 		handler, ok := remainHandlers[apiType]
 		if !ok {
 			continue // skip unsupported apiTypes
 		}
-		translatedText, err := handler(ctx, texts, target, endpoint)
+		translatedText, err := s.callAPI(ctx, apiType, handler, texts, target, endpoint)
 		if err == nil && translatedText != nil {
 			return translatedText, nil
 		}
-		log.Printf("[ERROR] Sequential API %s failed: %v", apiType, err)
+		loggerOf(s.opts).WarnContext(ctx, "API failed, trying the next one", "provider", ProviderGoogle, "api_type", apiType, errorAttr(err))
+		metricsOf(s.opts).Add(metrics.Fallbacks, 1, metrics.Labels{"provider": string(ProviderGoogle), "api_type": string(apiType)})
+		errs = append(errs, err)
 	}
-	return nil, errors.New("unable to translate text, all APIs failed")
+	return nil, allAPIsFailed(errs...)
+}
+
+// callAPI calls the handler of apiType in a tracing.APIAttempt span.
+func (s *GoogleTranslateService) callAPI(ctx context.Context, apiType GoogleAPIType, handler apiHandler, texts []string, target, endpoint string) ([]string, error) {
+	ctx, span := tracerOf(s.opts).Start(ctx, tracing.APIAttempt,
+		tracing.String(tracing.Provider, string(ProviderGoogle)), tracing.String(tracing.APIType, string(apiType)))
+	translated, err := handler(ctx, texts, target, endpoint)
+	endSpan(span, err)
+	return translated, err
 }
 
 func buildGoogleHTMLBody(texts []string, target string) string {
@@ -187,10 +307,21 @@ func buildGoogleHTMLBody(texts []string, target string) string {
 	return fmt.Sprintf(`[[[%s],"auto","%s"],"wt_lib"]`, quoted, target)
 }
 
+// errAllAPIsFailed is wrapped by the error returned when no Google API could translate the texts.
+var errAllAPIsFailed = errors.New("unable to translate text, all APIs failed")
+
+// allAPIsFailed wraps errAllAPIsFailed and the errors of the APIs that were tried.
+func allAPIsFailed(errs ...error) error {
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("%w: %w", errAllAPIsFailed, err)
+	}
+	return errAllAPIsFailed
+}
+
 type apiHandler func(ctx context.Context, texts []string, target, endpoint string) ([]string, error)
 
 func (s *GoogleTranslateService) getAPIHandlers() map[GoogleAPIType]apiHandler {
-	return map[GoogleAPIType]apiHandler{
+	handlers := map[GoogleAPIType]apiHandler{
 		TypeHtml: func(ctx context.Context, texts []string, target, endpoint string) ([]string, error) {
 			return s.callTranslateHTML(ctx, texts, target, endpoint)
 		},
@@ -213,6 +344,18 @@ func (s *GoogleTranslateService) getAPIHandlers() map[GoogleAPIType]apiHandler {
 			return s.callTranslateMix(ctx, texts, target)
 		},
 	}
+	// Tag the requests of every single API type so transports can tell them apart.
+	for apiType, handler := range handlers {
+		if apiType == TypeSequential || apiType == TypeMix {
+			continue
+		}
+		apiType, handler := apiType, handler
+		handlers[apiType] = func(ctx context.Context, texts []string, target, endpoint string) ([]string, error) {
+			ctx = utils.WithRequestInfo(ctx, utils.RequestInfo{Provider: string(ProviderGoogle), APIType: string(apiType)})
+			return handler(ctx, texts, target, endpoint)
+		}
+	}
+	return handlers
 }
 
 // executeAPIRequest handles the common logic for making API requests.
@@ -225,9 +368,15 @@ func (s *GoogleTranslateService) executeAPIRequest(
 	body []byte,
 	extractFunc func([]byte) ([]string, error),
 ) ([]string, error) {
-	respBytes, err := utils.DoRequest(s.client, ctx, method, endpoint, headers, params, body)
+	respBytes, err := doRequest(ctx, s.client, s.opts, method, endpoint, headers, params, body)
 	if err != nil {
 		return nil, err
 	}
 	return extractFunc(respBytes)
 }
+
+// isAbsoluteURL reports whether endpoint carries its own scheme and host.
+func isAbsoluteURL(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
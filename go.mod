@@ -2,7 +2,10 @@ module github.com/dinhcanh303/go_translate
 
 go 1.24.1
 
-require github.com/stretchr/testify v1.10.0
+require (
+	github.com/andybalholm/brotli v1.2.0
+	github.com/stretchr/testify v1.10.0
+)
 
 require (
 	golang.org/x/net v0.35.0 // indirect
@@ -0,0 +1,147 @@
+package go_translate
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/dinhcanh303/go_translate/utils"
+)
+
+// HeaderProfile is the coherent set of headers a browser sends, rotated as a unit instead of the User-Agent alone.
+type HeaderProfile struct {
+	// Name identifies the profile, e.g. "chrome-desktop".
+	Name string
+
+	// Headers are sent with every request, User-Agent included.
+	Headers map[string]string
+
+	// APIHeaders are added to the requests of one API type, keyed by GoogleAPIType or MicrosoftAPIType
+	// (e.g. the Origin and Referer of the page the API is called from).
+	APIHeaders map[string]map[string]string
+}
+
+// translatePageHeaders are sent by the translate.google.com page.
+var translatePageHeaders = map[string]string{
+	"Origin":  "https://translate.google.com",
+	"Referer": "https://translate.google.com/",
+}
+
+// chromeExtensionHeaders are sent by the Google Translate Chrome extension.
+var chromeExtensionHeaders = map[string]string{
+	"Origin": "chrome-extension://aapbdbdomjkkjkaonfhkkikfgjllcleb",
+}
+
+var (
+	// ProfileChromeDesktop is Chrome on desktop Linux calling the APIs from the translate.google.com page.
+	ProfileChromeDesktop = HeaderProfile{
+		Name: "chrome-desktop",
+		Headers: map[string]string{
+			"User-Agent":         DefaultUserAgents[0],
+			"Accept":             "*/*",
+			"Accept-Language":    "en-US,en;q=0.9",
+			"Sec-Ch-Ua":          `"Google Chrome";v="135", "Not-A.Brand";v="8", "Chromium";v="135"`,
+			"Sec-Ch-Ua-Mobile":   "?0",
+			"Sec-Ch-Ua-Platform": `"Linux"`,
+			"Sec-Fetch-Site":     "cross-site",
+			"Sec-Fetch-Mode":     "cors",
+			"Sec-Fetch-Dest":     "empty",
+		},
+		APIHeaders: map[string]map[string]string{
+			string(TypeHtml):  translatePageHeaders,
+			string(TypePaGtx): translatePageHeaders,
+		},
+	}
+
+	// ProfileEdge is Microsoft Edge on Windows, whose built-in translator calls the Edge API.
+	ProfileEdge = HeaderProfile{
+		Name: "edge",
+		Headers: map[string]string{
+			"User-Agent":         "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/135.0.0.0 Safari/537.36 Edg/135.0.0.0",
+			"Accept":             "*/*",
+			"Accept-Language":    "en-US,en;q=0.9",
+			"Sec-Ch-Ua":          `"Microsoft Edge";v="135", "Not-A.Brand";v="8", "Chromium";v="135"`,
+			"Sec-Ch-Ua-Mobile":   "?0",
+			"Sec-Ch-Ua-Platform": `"Windows"`,
+			"Sec-Fetch-Site":     "cross-site",
+			"Sec-Fetch-Mode":     "cors",
+			"Sec-Fetch-Dest":     "empty",
+		},
+		APIHeaders: map[string]map[string]string{
+			string(TypeHtml):  translatePageHeaders,
+			string(TypePaGtx): translatePageHeaders,
+		},
+	}
+
+	// ProfileFirefox is Firefox on Windows, which sends no client hints.
+	ProfileFirefox = HeaderProfile{
+		Name: "firefox",
+		Headers: map[string]string{
+			"User-Agent":      "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:137.0) Gecko/20100101 Firefox/137.0",
+			"Accept":          "*/*",
+			"Accept-Language": "en-US,en;q=0.5",
+			"Sec-Fetch-Site":  "cross-site",
+			"Sec-Fetch-Mode":  "cors",
+			"Sec-Fetch-Dest":  "empty",
+		},
+		APIHeaders: map[string]map[string]string{
+			string(TypeHtml):  translatePageHeaders,
+			string(TypePaGtx): translatePageHeaders,
+		},
+	}
+
+	// ProfileChromeExtension is Chrome on Windows calling the APIs from the Google Translate extension.
+	ProfileChromeExtension = HeaderProfile{
+		Name: "chrome-extension",
+		Headers: map[string]string{
+			"User-Agent":         "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/135.0.0.0 Safari/537.36",
+			"Accept":             "*/*",
+			"Accept-Language":    "en-US,en;q=0.9",
+			"Sec-Ch-Ua":          `"Google Chrome";v="135", "Not-A.Brand";v="8", "Chromium";v="135"`,
+			"Sec-Ch-Ua-Mobile":   "?0",
+			"Sec-Ch-Ua-Platform": `"Windows"`,
+			"Sec-Fetch-Site":     "none",
+			"Sec-Fetch-Mode":     "cors",
+			"Sec-Fetch-Dest":     "empty",
+		},
+		APIHeaders: map[string]map[string]string{
+			string(TypeHtml):      chromeExtensionHeaders,
+			string(TypePaGtx):     chromeExtensionHeaders,
+			string(TypeClientGtx): chromeExtensionHeaders,
+		},
+	}
+)
+
+// DefaultHeaderProfiles are used when TranslateOptions.HeaderProfiles is not set.
+var DefaultHeaderProfiles = []HeaderProfile{ProfileChromeDesktop, ProfileEdge, ProfileFirefox, ProfileChromeExtension}
+
+// requestHeaders returns the headers of a request of apiType: those of the header profile picked for the request,
+// then the given headers of the request itself.
+// The profile is random when opts.UseRandomUserAgents is set, the first one otherwise.
+// When random is enabled, a User-Agent of opts.CustomUserAgents replaces the one of the profile, whose
+// Sec-Ch-* client hints are dropped since they would describe another browser.
+func requestHeaders(opts *TranslateOptions, apiType string, headers map[string]string) map[string]string {
+	profiles := opts.HeaderProfiles
+	if len(profiles) == 0 {
+		profiles = DefaultHeaderProfiles
+	}
+	profile := utils.GetConditionalRandomValue(profiles, nil, opts.UseRandomUserAgents)
+
+	merged := make(map[string]string, len(profile.Headers)+len(headers)+2)
+	for _, set := range []map[string]string{profile.Headers, profile.APIHeaders[apiType]} {
+		for k, v := range set {
+			merged[http.CanonicalHeaderKey(k)] = v
+		}
+	}
+	if opts.UseRandomUserAgents && len(opts.CustomUserAgents) > 0 {
+		merged["User-Agent"] = utils.GetRandomValue(opts.CustomUserAgents)
+		for k := range merged {
+			if strings.HasPrefix(k, "Sec-Ch-") {
+				delete(merged, k)
+			}
+		}
+	}
+	for k, v := range headers {
+		merged[http.CanonicalHeaderKey(k)] = v
+	}
+	return merged
+}
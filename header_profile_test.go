@@ -0,0 +1,109 @@
+package go_translate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// headerServer answers the Google PaGtx and Dictionary APIs and records the headers of every request.
+func headerServer(t *testing.T) (*httptest.Server, *[]http.Header) {
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+		if r.URL.Path == "/dictionary" {
+			fmt.Fprint(w, `{"status":200,"translateResponse":{"translateText":"Xin chào"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"translation":"Xin chào","sourceLanguage":"en"}`)
+	}))
+	t.Cleanup(server.Close)
+	return server, &headers
+}
+
+func TestHeaderProfiles(t *testing.T) {
+	custom := HeaderProfile{
+		Name:       "custom",
+		Headers:    map[string]string{"User-Agent": "custom-agent", "accept-language": "vi-VN"},
+		APIHeaders: map[string]map[string]string{string(TypeDictionary): {"X-Referer": "overridden", "Origin": "https://custom.example.com"}},
+	}
+	scenarios := map[string]struct {
+		opts    TranslateOptions
+		apiType GoogleAPIType
+		want    map[string]string
+	}{
+		"default profile": {TranslateOptions{}, TypePaGtx, map[string]string{
+			"User-Agent":         DefaultUserAgents[0],
+			"Accept-Language":    "en-US,en;q=0.9",
+			"Sec-Ch-Ua-Platform": `"Linux"`,
+			"Origin":             "https://translate.google.com",
+			"Referer":            "https://translate.google.com/",
+		}},
+		"firefox sends no client hints": {TranslateOptions{HeaderProfiles: []HeaderProfile{ProfileFirefox}}, TypePaGtx, map[string]string{
+			"User-Agent": ProfileFirefox.Headers["User-Agent"],
+			"Sec-Ch-Ua":  "",
+			"Origin":     "https://translate.google.com",
+		}},
+		"custom profile, request headers win": {TranslateOptions{HeaderProfiles: []HeaderProfile{custom}}, TypeDictionary, map[string]string{
+			"User-Agent":      "custom-agent",
+			"Accept-Language": "vi-VN",
+			"Origin":          "https://custom.example.com",
+			"X-Referer":       "chrome-extension://mgijmajocgfcbeboacabfgobmjgjcoja",
+		}},
+		"custom user agents replace the profile one": {TranslateOptions{UseRandomUserAgents: true, CustomUserAgents: []string{"agent-1"}, HeaderProfiles: []HeaderProfile{ProfileEdge}}, TypePaGtx, map[string]string{
+			"User-Agent":         "agent-1",
+			"Accept-Language":    ProfileEdge.Headers["Accept-Language"],
+			"Sec-Ch-Ua":          "",
+			"Sec-Ch-Ua-Mobile":   "",
+			"Sec-Ch-Ua-Platform": "",
+		}},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			server, headers := headerServer(t)
+			opts := scenario.opts
+			opts.GoogleAPIType = scenario.apiType
+			opts.GoogleEndpoints = map[GoogleAPIType]string{TypePaGtx: server.URL + "/pa", TypeDictionary: server.URL + "/dictionary"}
+			translator, err := NewTranslator(&opts)
+			require.Nil(t, err)
+			_, err = translator.TranslateText(context.Background(), []string{"Hello"}, "vi")
+			require.Nil(t, err)
+			require.Len(t, *headers, 1)
+			for name, value := range scenario.want {
+				require.Equal(t, value, (*headers)[0].Get(name), name)
+			}
+		})
+	}
+}
+
+func TestHeaderProfilesRotateAsUnit(t *testing.T) {
+	server, headers := headerServer(t)
+	translator, err := NewTranslator(&TranslateOptions{
+		GoogleAPIType:       TypePaGtx,
+		UseRandomUserAgents: true,
+		GoogleEndpoints:     map[GoogleAPIType]string{TypePaGtx: server.URL},
+	})
+	require.Nil(t, err)
+	for i := 0; i < 40; i++ {
+		_, err := translator.TranslateText(context.Background(), []string{"Hello"}, "vi")
+		require.Nil(t, err)
+	}
+	byAgent := map[string]HeaderProfile{}
+	for _, profile := range DefaultHeaderProfiles {
+		byAgent[profile.Headers["User-Agent"]] = profile
+	}
+	used := map[string]bool{}
+	for _, header := range *headers {
+		profile, ok := byAgent[header.Get("User-Agent")]
+		require.True(t, ok, header.Get("User-Agent"))
+		used[profile.Name] = true
+		require.Equal(t, profile.Headers["Sec-Ch-Ua"], header.Get("Sec-Ch-Ua"))
+		require.Equal(t, profile.Headers["Accept-Language"], header.Get("Accept-Language"))
+		require.Equal(t, profile.APIHeaders[string(TypePaGtx)]["Origin"], header.Get("Origin"))
+	}
+	require.Greater(t, len(used), 1)
+}
@@ -0,0 +1,133 @@
+package go_translate
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/dinhcanh303/go_translate/utils"
+)
+
+// Doer sends an HTTP request. *http.Client implements it.
+type Doer = utils.Doer
+
+// DoerFunc adapts a function to the Doer interface.
+type DoerFunc = utils.DoerFunc
+
+// HTTPMiddleware wraps the Doer every provider request goes through. Set them as TranslateOptions.HTTPMiddlewares to
+// sign, log, measure or rewrite requests; utils.RequestInfoFrom(req.Context()) tells the provider and API type.
+type HTTPMiddleware = utils.Middleware
+
+const (
+	// DefaultRetryBackoff is the delay before the first retry when TranslateOptions.RetryBackoff is not set.
+	DefaultRetryBackoff = 500 * time.Millisecond
+
+	// MaxRetryBackoff caps the doubled delay between retries.
+	MaxRetryBackoff = time.Minute
+)
+
+// RetryMiddleware retries requests failing with a throttling (429), server (5xx) or network error up to maxRetries
+// times, waiting backoff (default DefaultRetryBackoff) before the first retry and doubling it on every following one,
+// up to MaxRetryBackoff.
+// It is installed around TranslateOptions.HTTPMiddlewares when TranslateOptions.MaxRetries is set.
+func RetryMiddleware(maxRetries int, backoff time.Duration) HTTPMiddleware {
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			for attempt := 0; ; attempt++ {
+				resp, err := next.Do(req)
+				if attempt >= maxRetries || !isRetryable(req.Context(), resp, err) || req.Body != nil && req.GetBody == nil {
+					return resp, err
+				}
+				if resp != nil {
+					io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+					resp.Body.Close()
+				}
+				req = req.Clone(withAttempt(req.Context(), attempt+2))
+				if req.GetBody != nil {
+					body, err := req.GetBody()
+					if err != nil {
+						return nil, err
+					}
+					req.Body = body
+				}
+				select {
+				case <-req.Context().Done():
+					return nil, req.Context().Err()
+				case <-time.After(retryDelay(backoff, attempt)):
+				}
+			}
+		})
+	}
+}
+
+// retryDelay returns backoff doubled attempt times, capped at MaxRetryBackoff unless backoff itself is longer.
+func retryDelay(backoff time.Duration, attempt int) time.Duration {
+	if backoff > MaxRetryBackoff>>attempt {
+		return max(backoff, MaxRetryBackoff)
+	}
+	return backoff << attempt
+}
+
+// isRetryable reports whether a request that got resp or err may succeed when sent again.
+func isRetryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		var netErr net.Error
+		return errors.As(err, &netErr)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return ctx.Value(keyRotationKey{}) == nil
+	}
+	return resp.StatusCode >= 500
+}
+
+type keyRotationKey struct{}
+
+// withKeyRotation marks the requests of ctx as sent with a key of a KeyPool: their 429 responses are not retried
+// by RetryMiddleware, since the pool retries them with another key.
+func withKeyRotation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, keyRotationKey{}, true)
+}
+
+// MaxResponseSizeMiddleware fails the reading of response bodies larger than max bytes with utils.ErrResponseTooLarge.
+// It counts the bytes as received, before decompression; TranslateOptions.MaxResponseSize limits the decoded body.
+func MaxResponseSizeMiddleware(max int64) HTTPMiddleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			resp.Body = utils.LimitBody(resp.Body, max)
+			return resp, nil
+		})
+	}
+}
+
+// requestDoer returns the Doer the requests of a provider are sent with: client wrapped with opts.HTTPMiddlewares,
+// with LogHTTPMiddleware, MetricsHTTPMiddleware and TracingHTTPMiddleware around them when opts.Logger, opts.Metrics
+// and opts.Tracer are set, and RetryMiddleware outermost when opts.MaxRetries is set.
+func requestDoer(client *http.Client, opts *TranslateOptions) Doer {
+	middlewares := opts.HTTPMiddlewares
+	if opts.Logger != nil {
+		middlewares = append([]HTTPMiddleware{LogHTTPMiddleware(opts.Logger)}, middlewares...)
+	}
+	if opts.Metrics != nil {
+		middlewares = append([]HTTPMiddleware{MetricsHTTPMiddleware(opts.Metrics)}, middlewares...)
+	}
+	if opts.Tracer != nil {
+		middlewares = append([]HTTPMiddleware{TracingHTTPMiddleware(opts.Tracer)}, middlewares...)
+	}
+	if opts.MaxRetries > 0 {
+		middlewares = append([]HTTPMiddleware{RetryMiddleware(opts.MaxRetries, opts.RetryBackoff)}, middlewares...)
+	}
+	return utils.Chain(client, middlewares...)
+}
@@ -0,0 +1,146 @@
+package go_translate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dinhcanh303/go_translate/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingMiddleware appends what it sees to log, prefixed with name.
+func recordingMiddleware(name string, log *[]string) HTTPMiddleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			info, _ := utils.RequestInfoFrom(req.Context())
+			*log = append(*log, fmt.Sprintf("%s> %s/%s %s", name, info.Provider, info.APIType, req.Method))
+			resp, err := next.Do(req)
+			if err == nil {
+				*log = append(*log, fmt.Sprintf("%s< %d", name, resp.StatusCode))
+			}
+			return resp, err
+		})
+	}
+}
+
+func TestHTTPMiddlewares(t *testing.T) {
+	libre := newLibreTranslateServer(t)
+	defer libre.Close()
+	google := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "signed", r.Header.Get("X-Signature"))
+		require.Equal(t, "/rewritten", r.URL.Path)
+		fmt.Fprint(w, `{"translation":"Xin chào","sourceLanguage":"en"}`)
+	}))
+	defer google.Close()
+
+	sign := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Signature", "signed")
+			return next.Do(req)
+		})
+	}
+	rewrite := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if info, _ := utils.RequestInfoFrom(req.Context()); info.Provider == string(ProviderGoogle) {
+				req.URL.Path = "/rewritten"
+			}
+			return next.Do(req)
+		})
+	}
+	tcs := map[string]struct {
+		opts     *TranslateOptions
+		expected []string
+	}{
+		"google": {
+			opts: &TranslateOptions{GoogleAPIType: TypePaGtx, GoogleEndpoints: map[GoogleAPIType]string{TypePaGtx: google.URL + "/v1/translate"}},
+			expected: []string{
+				"outer> google/pa-gtx GET", "inner> google/pa-gtx GET", "inner< 200", "outer< 200",
+			},
+		},
+		"official provider": {
+			opts: &TranslateOptions{Provider: ProviderLibreTranslate, ProviderOptions: LibreTranslateOptions{BaseURL: libre.URL, APIKey: "libre-key"}},
+			expected: []string{
+				"outer> libretranslate/ POST", "inner> libretranslate/ POST", "inner< 200", "outer< 200",
+			},
+		},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			var log []string
+			tc.opts.HTTPMiddlewares = []HTTPMiddleware{recordingMiddleware("outer", &log), sign, rewrite, recordingMiddleware("inner", &log)}
+			translator, err := NewTranslator(tc.opts)
+			require.Nil(t, err)
+			_, err = translator.TranslateText(context.Background(), []string{"Hello"}, "vi", "en")
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, log)
+		})
+	}
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		require.Equal(t, "payload", string(body))
+		if attempts.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	var log []string
+	doer := utils.Chain(server.Client(), RetryMiddleware(2, time.Millisecond), recordingMiddleware("attempt", &log))
+	resp, err := utils.DoRequest(doer, context.Background(), "POST", server.URL, nil, nil, []byte("payload"))
+	require.Nil(t, err)
+	require.Equal(t, "ok", string(resp))
+	// The middlewares inside the retry see every attempt.
+	require.Equal(t, []string{"attempt> / POST", "attempt< 503", "attempt> / POST", "attempt< 503", "attempt> / POST", "attempt< 200"}, log)
+
+	attempts.Store(0)
+	_, err = utils.DoRequest(utils.Chain(server.Client(), RetryMiddleware(1, time.Millisecond)), context.Background(), "POST", server.URL, nil, nil, []byte("payload"))
+	var httpErr *utils.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+	require.Equal(t, int32(2), attempts.Load())
+}
+
+func TestRetryDelay(t *testing.T) {
+	tcs := map[string]struct {
+		backoff  time.Duration
+		attempt  int
+		expected time.Duration
+	}{
+		"first retry":            {backoff: time.Second, attempt: 0, expected: time.Second},
+		"doubled":                {backoff: time.Second, attempt: 3, expected: 8 * time.Second},
+		"capped":                 {backoff: time.Second, attempt: 10, expected: MaxRetryBackoff},
+		"shift past 64 bits":     {backoff: time.Millisecond, attempt: 100, expected: MaxRetryBackoff},
+		"longer backoff is kept": {backoff: 2 * time.Minute, attempt: 1, expected: 2 * time.Minute},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, retryDelay(tc.backoff, tc.attempt))
+		})
+	}
+}
+
+func TestMaxResponseSizeMiddleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, strings.Repeat("a", 100))
+	}))
+	defer server.Close()
+
+	_, err := utils.DoRequest(utils.Chain(server.Client(), MaxResponseSizeMiddleware(50)), context.Background(), "GET", server.URL, nil, nil, nil)
+	require.ErrorIs(t, err, utils.ErrResponseTooLarge)
+	body, err := utils.DoRequest(utils.Chain(server.Client(), MaxResponseSizeMiddleware(100)), context.Background(), "GET", server.URL, nil, nil, nil)
+	require.Nil(t, err)
+	require.Len(t, body, 100)
+}
@@ -0,0 +1,169 @@
+package go_translate
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dinhcanh303/go_translate/utils"
+)
+
+// DefaultAPIKeyCooldown is how long a throttled key is quarantined when TranslateOptions.APIKeyCooldown is not set.
+const DefaultAPIKeyCooldown = time.Minute
+
+// ErrAllKeysQuarantined is returned when every key of a pool is quarantined after being throttled.
+var ErrAllKeysQuarantined = errors.New("all API keys are quarantined")
+
+// KeyRotation selects how the next key of a KeyPool is picked.
+type KeyRotation int
+
+const (
+	// RotateRoundRobin uses the keys in turn.
+	RotateRoundRobin KeyRotation = iota
+
+	// RotateLeastRecentlyThrottled uses the key throttled the longest time ago, keys never throttled first.
+	RotateLeastRecentlyThrottled
+)
+
+// KeyStats reports the usage of one key of a KeyPool.
+type KeyStats struct {
+	Key              string    // The API key
+	Uses             int64     // Requests sent with the key
+	Errors           int64     // Requests that failed, throttled ones included
+	Throttles        int64     // Requests answered with 403 or 429
+	QuarantinedUntil time.Time // End of the current quarantine, zero if the key is available
+}
+
+// KeyPool rotates among API keys and quarantines the keys answered with 403 or 429 for a cooldown period.
+// A pool of a single key never quarantines it, since there is no other key to use meanwhile.
+// It is safe for concurrent use.
+type KeyPool struct {
+	rotation KeyRotation
+	cooldown time.Duration
+	onChange func(index int, quarantined bool) // Called when a key is quarantined or used successfully again
+
+	mu   sync.Mutex
+	keys []*keyState
+	next int
+}
+
+type keyState struct {
+	KeyStats
+	lastUsed      time.Time
+	lastThrottled time.Time
+	quarantined   bool // Throttled and not used successfully since
+}
+
+// NewKeyPool creates a pool of keys picked with rotation and quarantined for cooldown (default DefaultAPIKeyCooldown).
+// Empty and duplicate keys are ignored.
+func NewKeyPool(keys []string, rotation KeyRotation, cooldown time.Duration) *KeyPool {
+	if cooldown <= 0 {
+		cooldown = DefaultAPIKeyCooldown
+	}
+	pool := &KeyPool{rotation: rotation, cooldown: cooldown}
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if _, ok := seen[key]; ok || key == "" {
+			continue
+		}
+		seen[key] = struct{}{}
+		pool.keys = append(pool.keys, &keyState{KeyStats: KeyStats{Key: key}})
+	}
+	return pool
+}
+
+// Next returns the key to use for the next request, or ErrAllKeysQuarantined.
+func (p *KeyPool) Next() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.keys) == 0 {
+		return "", errors.New("no API key configured")
+	}
+	now := time.Now()
+	var picked *keyState
+	switch p.rotation {
+	case RotateLeastRecentlyThrottled:
+		for _, key := range p.keys {
+			if now.Before(key.QuarantinedUntil) {
+				continue
+			}
+			if picked == nil || key.lastThrottled.Before(picked.lastThrottled) ||
+				key.lastThrottled.Equal(picked.lastThrottled) && key.lastUsed.Before(picked.lastUsed) {
+				picked = key
+			}
+		}
+	default:
+		for i := 0; i < len(p.keys) && picked == nil; i++ {
+			key := p.keys[(p.next+i)%len(p.keys)]
+			if !now.Before(key.QuarantinedUntil) {
+				picked = key
+				p.next = (p.next + i + 1) % len(p.keys)
+			}
+		}
+	}
+	if picked == nil {
+		return "", ErrAllKeysQuarantined
+	}
+	picked.QuarantinedUntil = time.Time{}
+	picked.lastUsed = now
+	picked.Uses++
+	return picked.Key, nil
+}
+
+// Report records the outcome of a request sent with key. A 403 or 429 response quarantines the key,
+// unless it is the only one of the pool.
+func (p *KeyPool) Report(key string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, state := range p.keys {
+		if state.Key != key {
+			continue
+		}
+		if err == nil {
+			if state.quarantined {
+				state.quarantined = false
+				p.changed(i, false)
+			}
+			return
+		}
+		state.Errors++
+		if isThrottled(err) {
+			state.Throttles++
+			state.lastThrottled = time.Now()
+			if len(p.keys) > 1 {
+				state.QuarantinedUntil = state.lastThrottled.Add(p.cooldown)
+				state.quarantined = true
+				p.changed(i, true)
+			}
+		}
+		return
+	}
+}
+
+// isThrottled reports whether err is a 403 or 429 response, which quarantines the key of the request.
+func isThrottled(err error) bool {
+	var httpErr *utils.HTTPError
+	return errors.As(err, &httpErr) && (httpErr.StatusCode == http.StatusForbidden || httpErr.StatusCode == http.StatusTooManyRequests)
+}
+
+func (p *KeyPool) changed(index int, quarantined bool) {
+	if p.onChange != nil {
+		p.onChange(index, quarantined)
+	}
+}
+
+// Stats returns the usage of every key, in the order they were given.
+func (p *KeyPool) Stats() []KeyStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	stats := make([]KeyStats, len(p.keys))
+	for i, key := range p.keys {
+		stats[i] = key.KeyStats
+		if !now.Before(stats[i].QuarantinedUntil) {
+			stats[i].QuarantinedUntil = time.Time{}
+		}
+	}
+	return stats
+}
@@ -0,0 +1,179 @@
+package go_translate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dinhcanh303/go_translate/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errThrottled = &utils.HTTPError{StatusCode: http.StatusTooManyRequests}
+
+func nextKeys(t *testing.T, pool *KeyPool, n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		key, err := pool.Next()
+		require.Nil(t, err)
+		keys[i] = key
+	}
+	return keys
+}
+
+func TestKeyPoolRotation(t *testing.T) {
+	scenarios := map[string]struct {
+		rotation KeyRotation
+		want     []string
+	}{
+		// "b" is quarantined after its first use, the other keys keep their turn.
+		"round robin": {RotateRoundRobin, []string{"b", "c", "a", "c", "a"}},
+		// "a" was throttled once, so "c" is preferred as long as it is never throttled.
+		"least recently throttled": {RotateLeastRecentlyThrottled, []string{"b", "c", "c", "c", "c"}},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			pool := NewKeyPool([]string{"a", "b", "", "c", "a"}, scenario.rotation, time.Hour)
+			key, err := pool.Next()
+			require.Nil(t, err)
+			require.Equal(t, "a", key)
+			pool.Report("a", errThrottled)
+			pool.Report("b", nil)
+			// Quarantine of "a" ends so it can be picked again; "b" is throttled on its first use.
+			pool.keys[0].QuarantinedUntil = time.Time{}
+
+			keys := []string{}
+			for i := 0; i < 5; i++ {
+				key, err := pool.Next()
+				require.Nil(t, err)
+				keys = append(keys, key)
+				if key == "b" {
+					pool.Report(key, &utils.HTTPError{StatusCode: http.StatusForbidden})
+				}
+			}
+			require.Equal(t, scenario.want, keys)
+		})
+	}
+}
+
+func TestKeyPoolQuarantine(t *testing.T) {
+	pool := NewKeyPool([]string{"a", "b"}, RotateRoundRobin, 20*time.Millisecond)
+	require.Equal(t, []string{"a", "b"}, nextKeys(t, pool, 2))
+	pool.Report("a", errThrottled)
+	pool.Report("b", errThrottled)
+	pool.Report("b", fmt.Errorf("network: %w", &utils.HTTPError{StatusCode: http.StatusInternalServerError}))
+
+	_, err := pool.Next()
+	require.ErrorIs(t, err, ErrAllKeysQuarantined)
+	stats := pool.Stats()
+	require.Equal(t, int64(1), stats[0].Throttles)
+	require.Equal(t, int64(2), stats[1].Errors)
+	require.Equal(t, int64(1), stats[1].Throttles)
+	require.False(t, stats[0].QuarantinedUntil.IsZero())
+
+	time.Sleep(30 * time.Millisecond)
+	require.Equal(t, []string{"a", "b"}, nextKeys(t, pool, 2))
+	for _, stat := range pool.Stats() {
+		require.Equal(t, int64(2), stat.Uses)
+		require.True(t, stat.QuarantinedUntil.IsZero())
+	}
+}
+
+func TestGoogleKeyPool(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("key")
+		keys = append(keys, key)
+		if key == "throttled" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"translation":"Xin chào","sourceLanguage":"en"}`)
+	}))
+	defer server.Close()
+
+	translator, err := NewTranslator(&TranslateOptions{
+		GoogleAPIType:            TypePaGtx,
+		GoogleAPIKeysTranslatePa: []string{"throttled", "ok"},
+		GoogleEndpoints:          map[GoogleAPIType]string{TypePaGtx: server.URL},
+		MaxRetries:               2,
+		RetryBackoff:             time.Millisecond,
+	})
+	require.Nil(t, err)
+	ctx := context.Background()
+
+	// The throttled key is quarantined and the request sent again at once with the next key, not retried with it.
+	for i := 0; i < 3; i++ {
+		result, err := translator.TranslateText(ctx, []string{"Hello"}, "vi")
+		require.Nil(t, err)
+		require.Equal(t, []string{"Xin chào"}, result)
+	}
+	require.Equal(t, []string{"throttled", "ok", "ok", "ok"}, keys)
+
+	stats := translator.(*GoogleTranslateService).KeyStats()[TypePaGtx]
+	require.Equal(t, KeyStats{Key: "ok", Uses: 3}, stats[1])
+	require.Equal(t, int64(1), stats[0].Throttles)
+	require.Equal(t, []KeyStats{{Key: GOOGLE_API_KEY_TRANSLATE_HTML}}, translator.(*GoogleTranslateService).KeyStats()[TypeHtml])
+}
+
+func TestGoogleKeyPoolExhausted(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	translator, err := NewTranslator(&TranslateOptions{
+		GoogleAPIType:            TypePaGtx,
+		GoogleAPIKeysTranslatePa: []string{"first", "second"},
+		GoogleEndpoints:          map[GoogleAPIType]string{TypePaGtx: server.URL},
+		MaxRetries:               2,
+		RetryBackoff:             time.Millisecond,
+	})
+	require.Nil(t, err)
+	_, err = translator.TranslateText(context.Background(), []string{"Hello"}, "vi")
+	require.ErrorIs(t, err, ErrAllKeysQuarantined)
+	var httpErr *utils.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusTooManyRequests, httpErr.StatusCode)
+	require.Equal(t, 2, requests)
+}
+
+func TestGoogleSingleKeyNotQuarantined(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, GOOGLE_API_KEY_TRANSLATE_PA, r.URL.Query().Get("key"))
+		if requests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"translation":"Xin chào","sourceLanguage":"en"}`)
+	}))
+	defer server.Close()
+
+	translator, err := NewTranslator(&TranslateOptions{
+		GoogleAPIType:   TypePaGtx,
+		GoogleEndpoints: map[GoogleAPIType]string{TypePaGtx: server.URL},
+	})
+	require.Nil(t, err)
+	ctx := context.Background()
+
+	// The default key is throttled, but the next request is still sent with it.
+	_, err = translator.TranslateText(ctx, []string{"Hello"}, "vi")
+	var httpErr *utils.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusTooManyRequests, httpErr.StatusCode)
+	result, err := translator.TranslateText(ctx, []string{"Hello"}, "vi")
+	require.Nil(t, err)
+	require.Equal(t, []string{"Xin chào"}, result)
+	require.Equal(t, 2, requests)
+
+	stats := translator.(*GoogleTranslateService).KeyStats()[TypePaGtx]
+	require.Equal(t, KeyStats{Key: GOOGLE_API_KEY_TRANSLATE_PA, Uses: 2, Errors: 1, Throttles: 1}, stats[0])
+}
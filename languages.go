@@ -0,0 +1,31 @@
+package go_translate
+
+import "strings"
+
+// NormalizeLanguageCode converts a language tag to its canonical BCP 47 casing.
+//
+// Underscores are accepted as separators, the language is lower-cased, scripts are title-cased
+// and regions are upper-cased, e.g. "en_gb" -> "en-GB", "ZH-HANS" -> "zh-Hans", "pt-br" -> "pt-BR".
+func NormalizeLanguageCode(code string) string {
+	parts := strings.FieldsFunc(strings.TrimSpace(code), func(r rune) bool { return r == '-' || r == '_' })
+	for i, part := range parts {
+		switch {
+		case i == 0:
+			parts[i] = strings.ToLower(part)
+		case len(part) == 4:
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		case len(part) == 2 || len(part) == 3:
+			parts[i] = strings.ToUpper(part)
+		default:
+			parts[i] = strings.ToLower(part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// splitLanguageCode returns the normalized base language and the remaining subtags of a language tag.
+func splitLanguageCode(code string) (string, string) {
+	normalized := NormalizeLanguageCode(code)
+	base, rest, _ := strings.Cut(normalized, "-")
+	return base, rest
+}
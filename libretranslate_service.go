@@ -0,0 +1,140 @@
+package go_translate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// LibreTranslateOptions configures ProviderLibreTranslate. Set it as TranslateOptions.ProviderOptions.
+type LibreTranslateOptions struct {
+	// BaseURL is the address of the LibreTranslate instance (e.g., "http://localhost:5000").
+	BaseURL string
+
+	// APIKey is sent as api_key when the instance requires one.
+	APIKey string
+
+	// Format is "text" (default) or "html".
+	Format string
+
+	// MaxChunkChars limits the characters sent per request; set it to the instance's --char-limit.
+	MaxChunkChars int
+}
+
+// ProviderName implements ProviderOptions.
+func (LibreTranslateOptions) ProviderName() Provider {
+	return ProviderLibreTranslate
+}
+
+// LibreTranslateService is a Translator, Detector and LanguageLister for a LibreTranslate instance.
+type LibreTranslateService struct {
+	client *http.Client      // HTTP client used for making API requests
+	opts   *TranslateOptions // Options for configuring the translation service
+	libre  LibreTranslateOptions
+}
+
+// NewLibreTranslateService validates the LibreTranslateOptions carried by opts and creates the service.
+func NewLibreTranslateService(client *http.Client, opts *TranslateOptions) (*LibreTranslateService, error) {
+	libre, _ := GetProviderOptions[LibreTranslateOptions](opts)
+	if libre.BaseURL == "" {
+		return nil, errors.New("libretranslate requires a base URL")
+	}
+	libre.BaseURL = strings.TrimRight(libre.BaseURL, "/")
+	if libre.Format == "" {
+		libre.Format = "text"
+	}
+	return &LibreTranslateService{client: client, opts: opts, libre: libre}, nil
+}
+
+// Capabilities reports the features supported by LibreTranslate.
+func (l *LibreTranslateService) Capabilities() Capabilities {
+	return Capabilities{Batch: true, Detection: true, HTML: true}
+}
+
+// TranslateText translates the texts with the /translate endpoint, sending them as a batch "q" array.
+func (l *LibreTranslateService) TranslateText(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+	source := sourceLanguage(detectedLangCode)
+	if source == "" {
+		source = "auto"
+	}
+	results := make([]string, 0, len(texts))
+	for _, chunk := range chunkTexts(texts, 0, l.libre.MaxChunkChars) {
+		var result struct {
+			TranslatedText []string `json:"translatedText"`
+		}
+		payload := map[string]any{
+			"q":      texts[chunk.start:chunk.end],
+			"source": source,
+			"target": target,
+			"format": l.libre.Format,
+		}
+		if err := l.call(ctx, "POST", "/translate", payload, &result); err != nil {
+			return nil, err
+		}
+		translated, err := checkTranslationCount(texts[chunk.start:chunk.end], result.TranslatedText)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, translated...)
+	}
+	return results, nil
+}
+
+// DetectLanguage returns the most likely language of every text using the /detect endpoint.
+func (l *LibreTranslateService) DetectLanguage(ctx context.Context, texts []string) ([]string, error) {
+	languages := make([]string, 0, len(texts))
+	for _, text := range texts {
+		var detections []struct {
+			Language   string  `json:"language"`
+			Confidence float64 `json:"confidence"`
+		}
+		if err := l.call(ctx, "POST", "/detect", map[string]any{"q": text}, &detections); err != nil {
+			return nil, err
+		}
+		language := ""
+		if len(detections) > 0 {
+			language = detections[0].Language
+		}
+		languages = append(languages, language)
+	}
+	return languages, nil
+}
+
+// Languages returns the languages served by the instance using the /languages endpoint.
+func (l *LibreTranslateService) Languages(ctx context.Context) ([]Language, error) {
+	var result []struct {
+		Code    string   `json:"code"`
+		Name    string   `json:"name"`
+		Targets []string `json:"targets"`
+	}
+	if err := l.call(ctx, "GET", "/languages", nil, &result); err != nil {
+		return nil, err
+	}
+	languages := make([]Language, 0, len(result))
+	for _, language := range result {
+		languages = append(languages, Language{Code: language.Code, Name: language.Name, Targets: language.Targets})
+	}
+	return languages, nil
+}
+
+// call sends a JSON payload to the instance, adding the API key, and decodes the JSON response into out.
+func (l *LibreTranslateService) call(ctx context.Context, method, path string, payload map[string]any, out any) error {
+	var body []byte
+	headers := map[string]string{}
+	if payload != nil {
+		if l.libre.APIKey != "" {
+			payload["api_key"] = l.libre.APIKey
+		}
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return err
+		}
+		headers["Content-Type"] = "application/json"
+	}
+	if err := doRequestJSON(ctx, l.client, l.opts, method, l.libre.BaseURL+path, headers, nil, body, out); err != nil {
+		return providerError(ProviderLibreTranslate, err)
+	}
+	return nil
+}
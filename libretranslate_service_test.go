@@ -0,0 +1,76 @@
+package go_translate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newLibreTranslateServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/translate", func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Q      []string `json:"q"`
+			Source string   `json:"source"`
+			Target string   `json:"target"`
+			APIKey string   `json:"api_key"`
+		}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&payload))
+		if payload.APIKey != "libre-key" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":"Invalid API key"}`))
+			return
+		}
+		var translated []string
+		for _, q := range payload.Q {
+			translated = append(translated, payload.Source+">"+payload.Target+":"+q)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"translatedText": translated})
+	})
+	mux.HandleFunc("/detect", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]any{{"confidence": 92.0, "language": "en"}})
+	})
+	mux.HandleFunc("/languages", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]any{{"code": "en", "name": "English", "targets": []string{"vi", "fr"}}})
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestLibreTranslate(t *testing.T) {
+	server := newLibreTranslateServer(t)
+	defer server.Close()
+
+	translator, err := NewTranslator(&TranslateOptions{
+		Provider:        ProviderLibreTranslate,
+		ProviderOptions: LibreTranslateOptions{BaseURL: server.URL, APIKey: "libre-key"},
+	})
+	require.Nil(t, err)
+	result, err := translator.TranslateText(context.Background(), []string{"Hello", "World"}, "vi")
+	require.Nil(t, err)
+	require.Equal(t, []string{"auto>vi:Hello", "auto>vi:World"}, result)
+
+	languages, err := translator.(Detector).DetectLanguage(context.Background(), []string{"Hello"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"en"}, languages)
+
+	supported, err := translator.(LanguageLister).Languages(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, []Language{{Code: "en", Name: "English", Targets: []string{"vi", "fr"}}}, supported)
+}
+
+func TestLibreTranslateError(t *testing.T) {
+	server := newLibreTranslateServer(t)
+	defer server.Close()
+
+	translator, err := NewTranslator(&TranslateOptions{
+		Provider:        ProviderLibreTranslate,
+		ProviderOptions: LibreTranslateOptions{BaseURL: server.URL},
+	})
+	require.Nil(t, err)
+	_, err = translator.TranslateText(context.Background(), []string{"Hello"}, "vi")
+	require.ErrorContains(t, err, "Invalid API key")
+}
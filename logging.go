@@ -0,0 +1,140 @@
+package go_translate
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dinhcanh303/go_translate/utils"
+)
+
+// discardLogger is used when TranslateOptions.Logger is not set, so the library stays quiet by default.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// loggerOf returns the logger of opts, or a logger discarding everything.
+func loggerOf(opts *TranslateOptions) *slog.Logger {
+	if opts == nil || opts.Logger == nil {
+		return discardLogger
+	}
+	return opts.Logger
+}
+
+type attemptKey struct{}
+
+// withAttempt returns a copy of ctx carrying the attempt number of a request, starting at 1.
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// attemptFrom returns the attempt number carried by ctx, 1 if none.
+func attemptFrom(ctx context.Context) int {
+	if attempt, ok := ctx.Value(attemptKey{}).(int); ok {
+		return attempt
+	}
+	return 1
+}
+
+// LogHTTPMiddleware logs every HTTP request with its provider, api_type, method, host, status, attempt and latency:
+// at debug level when it succeeds, at warn level when it fails or gets an error status.
+// It is installed inside the RetryMiddleware when TranslateOptions.Logger is set. Bodies are never logged,
+// and errors are logged without URL queries or body excerpts.
+func LogHTTPMiddleware(logger *slog.Logger) HTTPMiddleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.Do(req)
+			info, _ := utils.RequestInfoFrom(req.Context())
+			attrs := []slog.Attr{
+				slog.String("provider", info.Provider),
+				slog.String("api_type", info.APIType),
+				slog.String("method", req.Method),
+				slog.String("host", req.URL.Host),
+				slog.Int("attempt", attemptFrom(req.Context())),
+				slog.Duration("latency", time.Since(start)),
+			}
+			level, msg := slog.LevelDebug, "request sent"
+			if err != nil {
+				level, msg = slog.LevelWarn, "request failed"
+				attrs = append(attrs, errorAttr(err))
+			} else {
+				attrs = append(attrs, slog.Int("status", resp.StatusCode))
+				if resp.StatusCode >= 400 {
+					level, msg = slog.LevelWarn, "request failed"
+				}
+			}
+			logger.LogAttrs(req.Context(), level, msg, attrs...)
+			return resp, err
+		})
+	}
+}
+
+// LoggingMiddleware logs every translation of provider with its target, item count and latency: at debug level when
+// it succeeds, at warn level when it fails. The texts are logged only if logTexts is set; errors are logged
+// without the URL queries and body excerpts that may quote them.
+func LoggingMiddleware(logger *slog.Logger, provider string, logTexts bool) Middleware {
+	return func(next Translator) Translator {
+		return TranslatorFunc(func(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+			start := time.Now()
+			translated, err := next.TranslateText(ctx, texts, target, detectedLangCode...)
+			attrs := []slog.Attr{
+				slog.String("provider", provider),
+				slog.String("target", target),
+				slog.Int("items", len(texts)),
+				slog.Duration("latency", time.Since(start)),
+			}
+			if logTexts {
+				attrs = append(attrs, slog.Any("texts", texts))
+			}
+			if err != nil {
+				logger.LogAttrs(ctx, slog.LevelWarn, "translation failed", append(attrs, errorAttr(err))...)
+			} else {
+				logger.LogAttrs(ctx, slog.LevelDebug, "translation done", attrs...)
+			}
+			return translated, err
+		})
+	}
+}
+
+// errorAttr returns err as an "error" log attribute without the texts it may quote: the query of the URLs,
+// which carries the texts of GET requests, and the body excerpts of parse and blocked errors.
+func errorAttr(err error) slog.Attr {
+	msg := err.Error()
+	var redact func(err error)
+	redact = func(err error) {
+		switch e := err.(type) {
+		case *url.Error:
+			msg = strings.ReplaceAll(msg, strconv.Quote(e.URL), strconv.Quote(redactURL(e.URL)))
+		case *utils.ParseError:
+			msg = strings.ReplaceAll(msg, fmt.Sprintf(" (body: %q)", e.Excerpt), "")
+		case *utils.BlockedError:
+			msg = strings.ReplaceAll(msg, e.URL, redactURL(e.URL))
+		}
+		switch wrapper := err.(type) {
+		case interface{ Unwrap() error }:
+			if inner := wrapper.Unwrap(); inner != nil {
+				redact(inner)
+			}
+		case interface{ Unwrap() []error }:
+			for _, inner := range wrapper.Unwrap() {
+				redact(inner)
+			}
+		}
+	}
+	redact(err)
+	return slog.String("error", msg)
+}
+
+// redactURL returns rawURL without its query, fragment and password.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "<invalid URL>"
+	}
+	u.RawQuery, u.ForceQuery, u.Fragment, u.RawFragment = "", false, "", ""
+	return u.Redacted()
+}
@@ -0,0 +1,191 @@
+package go_translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dinhcanh303/go_translate/translatetest"
+	"github.com/stretchr/testify/require"
+)
+
+// logRecords decodes the JSON log lines written to buf.
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		require.Nil(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLogger(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls++; calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"translation":"Xin chào","sourceLanguage":"en"}`)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	translator, err := NewTranslator(&TranslateOptions{
+		GoogleAPIType:   TypePaGtx,
+		GoogleEndpoints: map[GoogleAPIType]string{TypePaGtx: server.URL},
+		MaxRetries:      1,
+		RetryBackoff:    time.Millisecond,
+		Logger:          slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	})
+	require.Nil(t, err)
+	_, err = translator.TranslateText(context.Background(), []string{"Secret text"}, "vi")
+	require.Nil(t, err)
+
+	records := logRecords(t, &buf)
+	require.Len(t, records, 2)
+	for i, expected := range []struct {
+		level  string
+		status float64
+	}{{"WARN", 503}, {"DEBUG", 200}} {
+		record := records[i]
+		require.Equal(t, expected.level, record["level"])
+		require.Equal(t, expected.status, record["status"])
+		require.Equal(t, float64(i+1), record["attempt"])
+		require.Equal(t, "google", record["provider"])
+		require.Equal(t, "pa-gtx", record["api_type"])
+		require.Equal(t, strings.TrimPrefix(server.URL, "http://"), record["host"])
+		require.Contains(t, record, "latency")
+	}
+	require.NotContains(t, buf.String(), "Secret")
+}
+
+func TestLoggerRedactsErrors(t *testing.T) {
+	// The pa-gtx API answers with a broken body quoting the text, the other APIs are unreachable.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"translation":%q`, r.URL.Query().Get("query.text"))
+	}))
+	defer server.Close()
+	endpoints := map[GoogleAPIType]string{}
+	for _, apiType := range GoogleAPITypeSupport {
+		endpoints[apiType] = deadProxyURL() + "/translate"
+	}
+	endpoints[TypePaGtx] = server.URL
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	translator, err := NewTranslator(&TranslateOptions{
+		GoogleAPIType:   TypeSequential,
+		GoogleEndpoints: endpoints,
+		Logger:          logger,
+		Middlewares:     []Middleware{LoggingMiddleware(logger, "google", false)},
+	})
+	require.Nil(t, err)
+	_, err = translator.TranslateText(context.Background(), []string{"Secret text"}, "vi")
+	require.NotNil(t, err)
+
+	out := buf.String()
+	require.Contains(t, out, "request failed")
+	require.Contains(t, out, "API failed, trying the next one")
+	require.Contains(t, out, "translation failed")
+	require.Contains(t, out, "unexpected pa-gtx response")
+	require.NotContains(t, out, "Secret")
+	require.NotContains(t, out, "key=")
+}
+
+func TestLoggerReportsFailedAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	translator, err := NewTranslator(&TranslateOptions{
+		GoogleAPIType:   TypePaGtx,
+		GoogleEndpoints: map[GoogleAPIType]string{TypePaGtx: server.URL},
+		Logger:          slog.New(slog.NewJSONHandler(&buf, nil)),
+	})
+	require.Nil(t, err)
+	_, err = translator.TranslateText(context.Background(), []string{"Secret text"}, "vi")
+	require.NotNil(t, err)
+
+	var failed map[string]any
+	for _, record := range logRecords(t, &buf) {
+		if record["msg"] == "API failed" {
+			failed = record
+		}
+	}
+	require.NotNil(t, failed)
+	require.Equal(t, "WARN", failed["level"])
+	require.Equal(t, "google", failed["provider"])
+	require.Equal(t, "pa-gtx", failed["api_type"])
+	require.Contains(t, failed["error"], "503")
+	require.NotContains(t, buf.String(), "Secret")
+}
+
+func TestLoggerQuietByDefault(t *testing.T) {
+	var buf bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&buf)
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	endpoints := map[GoogleAPIType]string{}
+	for _, apiType := range GoogleAPITypeSupport {
+		endpoints[apiType] = server.URL
+	}
+	translator, err := NewTranslator(&TranslateOptions{GoogleAPIType: TypeSequential, GoogleEndpoints: endpoints, AddToken: true, TKKSource: failingTKK{}})
+	require.Nil(t, err)
+	_, err = translator.TranslateText(context.Background(), []string{"Hello"}, "vi")
+	require.NotNil(t, err)
+	require.Empty(t, buf.String())
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	tcs := map[string]struct {
+		logTexts bool
+		fail     bool
+		expected map[string]any
+	}{
+		"failure": {fail: true, expected: map[string]any{"level": "WARN", "msg": "translation failed", "error": "boom", "items": float64(2)}},
+		"success": {expected: map[string]any{"level": "DEBUG", "msg": "translation done", "target": "vi", "provider": "fake"}},
+		"texts":   {logTexts: true, expected: map[string]any{"texts": []any{"Hello", "World"}}},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			fake := translatetest.NewFake()
+			if tc.fail {
+				fake.FailNext(errors.New("boom"))
+			}
+			_, _ = Chain(fake, LoggingMiddleware(logger, "fake", tc.logTexts)).TranslateText(context.Background(), []string{"Hello", "World"}, "vi")
+
+			records := logRecords(t, &buf)
+			require.Len(t, records, 1)
+			for key, value := range tc.expected {
+				require.Equal(t, value, records[0][key], key)
+			}
+			if !tc.logTexts {
+				require.NotContains(t, buf.String(), "Hello")
+			}
+		})
+	}
+}
@@ -3,23 +3,60 @@ package go_translate
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/dinhcanh303/go_translate/metrics"
+	"github.com/dinhcanh303/go_translate/tracing"
 	"github.com/dinhcanh303/go_translate/utils"
 )
 
 // MicrosoftTranslateService is a service for interacting with Microsoft's translation API.
 type MicrosoftTranslateService struct {
-	client *http.Client      // HTTP client to send requests
-	opts   *TranslateOptions // Options that can be used for customizing translation behavior (e.g., API keys, etc.)
+	client    *http.Client                // HTTP client to send requests
+	opts      *TranslateOptions           // Options that can be used for customizing translation behavior (e.g., API keys, etc.)
+	endpoints map[MicrosoftAPIType]string // Endpoint of every API type, defaults merged with opts.MicrosoftEndpoints
+	authURL   string                      // URL of the Edge authorization token
+
+	mu          sync.Mutex    // Guards the cached Edge token
+	token       string        // Cached Edge token
+	tokenExpiry time.Time     // Expiry of the cached Edge token
+	refreshing  chan struct{} // Closed when the refresh in progress, if any, completes
 }
 
+const (
+	// edgeTokenRefreshMargin is how long before its expiry the Edge token is refreshed.
+	edgeTokenRefreshMargin = time.Minute
+
+	// edgeTokenDefaultLifetime is how long a token whose expiry cannot be decoded is cached.
+	edgeTokenDefaultLifetime = 5 * time.Minute
+)
+
+// edgeTokenCacheLabels label the metrics.CacheHits and metrics.CacheMisses of the Edge token.
+var edgeTokenCacheLabels = metrics.Labels{"provider": string(ProviderMicrosoft), "cache": "edge_token"}
+
 // NewMicrosoftTranslateService creates a new instance of MicrosoftTranslateService with the provided options.
 func NewMicrosoftTranslateService(client *http.Client, opts *TranslateOptions) *MicrosoftTranslateService {
+	endpoints := make(map[MicrosoftAPIType]string, len(MicrosoftUrls))
+	for apiType, endpoint := range MicrosoftUrls {
+		endpoints[apiType] = endpoint
+	}
+	for apiType, endpoint := range opts.MicrosoftEndpoints {
+		endpoints[apiType] = endpoint
+	}
+	authURL := opts.MicrosoftAuthURL
+	if authURL == "" {
+		authURL = AuthEdgeUrl
+	}
 	return &MicrosoftTranslateService{
-		client: client,
-		opts:   opts,
+		client:    client,
+		opts:      opts,
+		endpoints: endpoints,
+		authURL:   authURL,
 	}
 }
 
@@ -29,21 +66,28 @@ func (m *MicrosoftTranslateService) TranslateText(ctx context.Context, texts []s
 	return m.translate(ctx, texts, target, detectedLangCode...)
 }
 
+// Capabilities reports the features supported by the unofficial Microsoft endpoints.
+func (m *MicrosoftTranslateService) Capabilities() Capabilities {
+	return Capabilities{Batch: true}
+}
+
 // TranslateText performs the translation of the provided text into the target language using the Microsoft translation API.
 // It also optionally accepts a detected language code if you want to specify the source language explicitly.
 func (m *MicrosoftTranslateService) translate(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+	apiType := TypeEdge
 	if m.opts.MicrosoftAPIType == TypeSmartLink {
+		apiType = TypeSmartLink
+	}
+	ctx = utils.WithRequestInfo(ctx, utils.RequestInfo{Provider: string(ProviderMicrosoft), APIType: string(apiType)})
+	if apiType == TypeSmartLink {
 		return m.callTranslateSmartLink(ctx, texts, target, detectedLangCode...)
 	}
 	return m.callTranslateEdge(ctx, texts, target)
 }
 
 // callTranslateEdge makes a POST request to the Edge API endpoint and returns the translated text.
+// The request is sent again once with a fresh token if the cached one is rejected.
 func (m *MicrosoftTranslateService) callTranslateEdge(ctx context.Context, texts []string, target string) ([]string, error) {
-	tokenBytes, err := utils.DoRequest(m.client, ctx, "GET", AuthEdgeUrl, nil, nil, nil)
-	if err != nil {
-		return nil, err
-	}
 	var payload []map[string]string
 	for _, text := range texts {
 		payload = append(payload, map[string]string{
@@ -54,17 +98,105 @@ func (m *MicrosoftTranslateService) callTranslateEdge(ctx context.Context, texts
 	if err != nil {
 		return nil, err
 	}
-	baseUrl := MicrosoftUrls[TypeEdge] + target
-	header := map[string]string{
-		"Content-Type":  "application/json",
-		"Authorization": string(tokenBytes),
-		"User-Agent":    utils.GetConditionalRandomValue(DefaultUserAgents, m.opts.CustomUserAgents, m.opts.UseRandomUserAgents),
+	baseUrl := m.endpoints[TypeEdge] + target
+	for attempt := 0; ; attempt++ {
+		token, err := m.edgeToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		header := requestHeaders(m.opts, string(TypeEdge), map[string]string{
+			"Content-Type":  "application/json",
+			"Authorization": token,
+		})
+		resq, err := doRequest(ctx, m.client, m.opts, "POST", baseUrl, header, nil, jsonPayload)
+		var httpErr *utils.HTTPError
+		if attempt == 0 && errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusUnauthorized {
+			m.invalidateEdgeToken(token)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return utils.ExtractTranslatedTextFromMCSEdge(resq)
+	}
+}
+
+// edgeToken returns the cached Edge token, fetching a new one when it is about to expire.
+// Concurrent callers share a single refresh; while it runs, callers keep using the current token if it has not expired yet.
+func (m *MicrosoftTranslateService) edgeToken(ctx context.Context) (string, error) {
+	m.mu.Lock()
+	for {
+		now := time.Now()
+		if m.token != "" && now.Before(m.tokenExpiry.Add(-edgeTokenRefreshMargin)) {
+			token := m.token
+			m.mu.Unlock()
+			metricsOf(m.opts).Add(metrics.CacheHits, 1, edgeTokenCacheLabels)
+			return token, nil
+		}
+		if m.refreshing == nil {
+			break
+		}
+		if m.token != "" && now.Before(m.tokenExpiry) {
+			token := m.token
+			m.mu.Unlock()
+			metricsOf(m.opts).Add(metrics.CacheHits, 1, edgeTokenCacheLabels)
+			return token, nil
+		}
+		refreshing := m.refreshing
+		m.mu.Unlock()
+		select {
+		case <-refreshing:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		m.mu.Lock()
 	}
-	resq, err := utils.DoRequest(m.client, ctx, "POST", baseUrl, header, nil, jsonPayload)
+	done := make(chan struct{})
+	m.refreshing = done
+	m.mu.Unlock()
+	metricsOf(m.opts).Add(metrics.CacheMisses, 1, edgeTokenCacheLabels)
+
+	token, expiry, err := m.fetchEdgeToken(ctx)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.refreshing = nil
+	close(done)
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+	m.token, m.tokenExpiry = token, expiry
+	return token, nil
+}
+
+// fetchEdgeToken requests a new Edge token and decodes its expiry.
+// The fetch is traced as a tracing.TokenFetch span.
+func (m *MicrosoftTranslateService) fetchEdgeToken(ctx context.Context) (token string, expiry time.Time, err error) {
+	ctx, span := tracerOf(m.opts).Start(ctx, tracing.TokenFetch,
+		tracing.String(tracing.Provider, string(ProviderMicrosoft)), tracing.String(tracing.Token, "edge_token"))
+	defer func() { endSpan(span, err) }()
+	tokenBytes, err := doRequest(ctx, m.client, m.opts, "GET", m.authURL, nil, nil, nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	token = strings.TrimSpace(string(tokenBytes))
+	if token == "" {
+		return "", time.Time{}, errors.New("microsoft edge returned an empty token")
+	}
+	expiry, err = utils.JWTExpiry(token)
+	if err != nil {
+		expiry = time.Now().Add(edgeTokenDefaultLifetime)
+	}
+	return token, expiry, nil
+}
+
+// invalidateEdgeToken drops the cached token if it is still the rejected one.
+func (m *MicrosoftTranslateService) invalidateEdgeToken(token string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.token == token {
+		m.token = ""
 	}
-	return utils.ExtractTranslatedTextFromMCSEdge(resq)
 }
 
 // callTranslateSmartLink makes a POST request to the Microsoft translate API endpoint of smart link and returns the translated text.
@@ -80,11 +212,10 @@ func (m *MicrosoftTranslateService) callTranslateSmartLink(ctx context.Context,
 		"dir":      {dir},
 		"provider": {"microsoft"},
 	}
-	header := map[string]string{
+	header := requestHeaders(m.opts, string(TypeSmartLink), map[string]string{
 		"Content-Type": "application/x-www-form-urlencoded",
-		"User-Agent":   utils.GetConditionalRandomValue(DefaultUserAgents, m.opts.CustomUserAgents, m.opts.UseRandomUserAgents),
-	}
-	resp, err := utils.DoRequest(m.client, ctx, "POST", MicrosoftServerUrl, header, formData, nil)
+	})
+	resp, err := doRequest(ctx, m.client, m.opts, "POST", m.endpoints[TypeSmartLink], header, formData, nil)
 	if err != nil {
 		return nil, err
 	}
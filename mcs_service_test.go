@@ -0,0 +1,109 @@
+package go_translate
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// edgeServer is a stand-in for the Edge auth and translate endpoints issuing tokens valid for lifetime.
+type edgeServer struct {
+	*httptest.Server
+	lifetime  time.Duration
+	auths     atomic.Int32
+	revoked   sync.Map    // Tokens answered with 401
+	revokeAll atomic.Bool // Answer every translation with 401
+}
+
+func newEdgeServer(t *testing.T, lifetime time.Duration) *edgeServer {
+	s := &edgeServer{lifetime: lifetime}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth", func(w http.ResponseWriter, r *http.Request) {
+		n := s.auths.Add(1)
+		claims := fmt.Sprintf(`{"n":%d,"exp":%d}`, n, time.Now().Add(s.lifetime).Unix())
+		fmt.Fprintf(w, "eyJhbGciOiJIUzI1NiJ9.%s.c2ln", base64.RawURLEncoding.EncodeToString([]byte(claims)))
+	})
+	mux.HandleFunc("/translate", func(w http.ResponseWriter, r *http.Request) {
+		if _, revoked := s.revoked.Load(r.Header.Get("Authorization")); revoked || s.revokeAll.Load() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[{"translations":[{"text":"Xin chào","to":"vi"}]}]`))
+	})
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *edgeServer) translator(t *testing.T) *MicrosoftTranslateService {
+	translator, err := NewTranslator(&TranslateOptions{
+		Provider:           ProviderMicrosoft,
+		MicrosoftAuthURL:   s.URL + "/auth",
+		MicrosoftEndpoints: map[MicrosoftAPIType]string{TypeEdge: s.URL + "/translate?to="},
+	})
+	require.Nil(t, err)
+	return translator.(*MicrosoftTranslateService)
+}
+
+func TestEdgeTokenIsCached(t *testing.T) {
+	server := newEdgeServer(t, 10*time.Minute)
+	translator := server.translator(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := translator.TranslateText(ctx, []string{"Hello"}, "vi")
+			require.Nil(t, err)
+			require.Equal(t, []string{"Xin chào"}, result)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), server.auths.Load())
+}
+
+func TestEdgeTokenRefreshedBeforeExpiry(t *testing.T) {
+	// Tokens expiring within the refresh margin are replaced on every use.
+	server := newEdgeServer(t, edgeTokenRefreshMargin/2)
+	translator := server.translator(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := translator.TranslateText(ctx, []string{"Hello"}, "vi")
+		require.Nil(t, err)
+	}
+	require.Equal(t, int32(3), server.auths.Load())
+}
+
+func TestEdgeTokenRefetchedOnUnauthorized(t *testing.T) {
+	server := newEdgeServer(t, 10*time.Minute)
+	translator := server.translator(t)
+	ctx := context.Background()
+
+	_, err := translator.TranslateText(ctx, []string{"Hello"}, "vi")
+	require.Nil(t, err)
+	token, err := translator.edgeToken(ctx)
+	require.Nil(t, err)
+	server.revoked.Store(token, true)
+
+	result, err := translator.TranslateText(ctx, []string{"Hello"}, "vi")
+	require.Nil(t, err)
+	require.Equal(t, []string{"Xin chào"}, result)
+	require.Equal(t, int32(2), server.auths.Load())
+
+	// A token rejected again after the refetch is reported instead of looping.
+	server.revokeAll.Store(true)
+	_, err = translator.TranslateText(ctx, []string{"Hello"}, "vi")
+	require.NotNil(t, err)
+	require.Equal(t, int32(3), server.auths.Load())
+}
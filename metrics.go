@@ -0,0 +1,72 @@
+package go_translate
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dinhcanh303/go_translate/metrics"
+	"github.com/dinhcanh303/go_translate/utils"
+)
+
+// Metrics receives the measurements of the library, named and labeled as documented in the metrics package.
+// The metrics/prometheus and metrics/expvar packages implement it.
+type Metrics = metrics.Metrics
+
+// metricsOf returns the metrics of opts, or an implementation discarding them.
+func metricsOf(opts *TranslateOptions) Metrics {
+	if opts == nil || opts.Metrics == nil {
+		return metrics.Nop{}
+	}
+	return opts.Metrics
+}
+
+// MetricsHTTPMiddleware measures every HTTP request: metrics.Requests, metrics.RequestLatency and,
+// for the attempts after the first one, metrics.Retries.
+// It is installed inside the RetryMiddleware when TranslateOptions.Metrics is set.
+func MetricsHTTPMiddleware(m Metrics) HTTPMiddleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.Do(req)
+			info, _ := utils.RequestInfoFrom(req.Context())
+			labels := metrics.Labels{"provider": info.Provider, "api_type": info.APIType}
+			m.Observe(metrics.RequestLatency, time.Since(start).Seconds(), labels)
+			if attemptFrom(req.Context()) > 1 {
+				m.Add(metrics.Retries, 1, labels)
+			}
+			status := "error"
+			if err == nil {
+				status = strconv.Itoa(resp.StatusCode)
+			}
+			m.Add(metrics.Requests, 1, metrics.Labels{"provider": info.Provider, "api_type": info.APIType, "status": status})
+			return resp, err
+		})
+	}
+}
+
+// MetricsMiddleware measures every translation of provider: metrics.Translations, metrics.Characters and
+// metrics.TranslationLatency, labeled by target language. It wraps the provider when TranslateOptions.Metrics is set.
+func MetricsMiddleware(m Metrics, provider string) Middleware {
+	return func(next Translator) Translator {
+		return TranslatorFunc(func(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+			start := time.Now()
+			translated, err := next.TranslateText(ctx, texts, target, detectedLangCode...)
+			labels := metrics.Labels{"provider": provider, "target": target}
+			m.Observe(metrics.TranslationLatency, time.Since(start).Seconds(), labels)
+			characters := 0
+			for _, text := range texts {
+				characters += utf8.RuneCountInString(text)
+			}
+			m.Add(metrics.Characters, float64(characters), labels)
+			outcome := "success"
+			if err != nil {
+				outcome = "failure"
+			}
+			m.Add(metrics.Translations, 1, metrics.Labels{"provider": provider, "target": target, "outcome": outcome})
+			return translated, err
+		})
+	}
+}
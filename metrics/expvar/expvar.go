@@ -0,0 +1,93 @@
+// Package expvar publishes the go_translate metrics with the standard expvar package, served at /debug/vars.
+//
+//	translator, _ := go_translate.NewTranslator(&go_translate.TranslateOptions{Metrics: expvar.New("go_translate")})
+package expvar
+
+import (
+	stdexpvar "expvar"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dinhcanh303/go_translate/metrics"
+)
+
+// Metrics implements metrics.Metrics on an expvar.Map holding one entry per metric and labels,
+// e.g. `translate_translations_total{outcome="success",provider="google"}`.
+// Counters and gauges are numbers; histograms are maps with their "count" and "sum".
+type Metrics struct {
+	vars *stdexpvar.Map
+}
+
+// mu serializes the creation of histograms and gauges, shared by the Metrics publishing the same map.
+var mu sync.Mutex
+
+// New publishes a Metrics under name. If name is already published as an expvar.Map, e.g. by an earlier
+// call, the metrics are recorded in that map; New panics if name is published with another type.
+func New(name string) *Metrics {
+	if existing := stdexpvar.Get(name); existing != nil {
+		vars, ok := existing.(*stdexpvar.Map)
+		if !ok {
+			panic(fmt.Sprintf("expvar: %s is already published as a %T", name, existing))
+		}
+		return &Metrics{vars: vars}
+	}
+	m := &Metrics{vars: new(stdexpvar.Map)}
+	stdexpvar.Publish(name, m.vars)
+	return m
+}
+
+// Map returns the map the metrics are published in.
+func (m *Metrics) Map() *stdexpvar.Map {
+	return m.vars
+}
+
+// Add implements metrics.Metrics.
+func (m *Metrics) Add(name string, value float64, labels metrics.Labels) {
+	m.vars.AddFloat(key(name, labels), value)
+}
+
+// Observe implements metrics.Metrics.
+func (m *Metrics) Observe(name string, value float64, labels metrics.Labels) {
+	k := key(name, labels)
+	mu.Lock()
+	h, ok := m.vars.Get(k).(*stdexpvar.Map)
+	if !ok {
+		h = new(stdexpvar.Map)
+		m.vars.Set(k, h)
+	}
+	mu.Unlock()
+	h.Add("count", 1)
+	h.AddFloat("sum", value)
+}
+
+// Set implements metrics.Metrics.
+func (m *Metrics) Set(name string, value float64, labels metrics.Labels) {
+	k := key(name, labels)
+	mu.Lock()
+	v, ok := m.vars.Get(k).(*stdexpvar.Float)
+	if !ok {
+		v = new(stdexpvar.Float)
+		m.vars.Set(k, v)
+	}
+	mu.Unlock()
+	v.Set(value)
+}
+
+// key renders name and labels sorted by name, e.g. `name{provider="google",target="vi"}`.
+func key(name string, labels metrics.Labels) string {
+	if len(labels) == 0 {
+		return name
+	}
+	names := make([]string, 0, len(labels))
+	for label := range labels {
+		names = append(names, label)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, label := range names {
+		parts[i] = label + `="` + labels[label] + `"`
+	}
+	return name + "{" + strings.Join(parts, ",") + "}"
+}
@@ -0,0 +1,51 @@
+package expvar
+
+import (
+	stdexpvar "expvar"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dinhcanh303/go_translate/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+// uniqueName returns a name never published before, even when the tests run several times.
+func uniqueName() string {
+	return fmt.Sprintf("go_translate_test_%d", names.Add(1))
+}
+
+var names atomic.Int64
+
+func TestMetrics(t *testing.T) {
+	name := uniqueName()
+	m := New(name)
+	require.Equal(t, m.Map(), stdexpvar.Get(name))
+
+	labels := metrics.Labels{"provider": "google", "target": "vi"}
+	m.Add(metrics.Characters, 5, labels)
+	m.Add(metrics.Characters, 3, labels)
+	m.Set(metrics.BreakerState, 1, metrics.Labels{"kind": "proxy"})
+	m.Set(metrics.BreakerState, 0, metrics.Labels{"kind": "proxy"})
+	m.Observe(metrics.TranslationLatency, 0.25, labels)
+	m.Observe(metrics.TranslationLatency, 0.5, labels)
+	m.Add("custom_total", 1, nil)
+
+	require.Equal(t, "8", m.Map().Get(`translate_characters_total{provider="google",target="vi"}`).String())
+	require.Equal(t, "0", m.Map().Get(`translate_breaker_state{kind="proxy"}`).String())
+	require.Equal(t, "1", m.Map().Get("custom_total").String())
+	require.JSONEq(t, `{"count":2,"sum":0.75}`, m.Map().Get(`translate_translation_duration_seconds{provider="google",target="vi"}`).String())
+}
+
+func TestNewPublishedName(t *testing.T) {
+	name := uniqueName()
+	first := New(name)
+	first.Add(metrics.Characters, 5, nil)
+	second := New(name)
+	second.Add(metrics.Characters, 3, nil)
+	require.Same(t, first.Map(), second.Map())
+	require.Equal(t, "8", second.Map().Get(metrics.Characters).String())
+
+	stdexpvar.NewInt(name + "_int")
+	require.Panics(t, func() { New(name + "_int") })
+}
@@ -0,0 +1,73 @@
+// Package metrics defines the measurements emitted by go_translate and the interface receiving them.
+// The prometheus and expvar subpackages provide ready-made implementations without third-party dependencies.
+package metrics
+
+// Labels qualify a measurement, e.g. {"provider": "google", "api_type": "html", "target": "vi"}.
+type Labels map[string]string
+
+// Metrics receives the measurements of the library. Implementations must be safe for concurrent use.
+type Metrics interface {
+	// Add increases the counter name by value.
+	Add(name string, value float64, labels Labels)
+
+	// Observe records value in the histogram name.
+	Observe(name string, value float64, labels Labels)
+
+	// Set sets the gauge name to value.
+	Set(name string, value float64, labels Labels)
+}
+
+// Names of the measurements emitted by the library.
+const (
+	// Translations counts the translation calls, labeled by provider, target and outcome ("success" or "failure").
+	Translations = "translate_translations_total"
+
+	// Characters counts the characters sent for translation, labeled by provider and target.
+	Characters = "translate_characters_total"
+
+	// TranslationLatency is the duration of the translation calls in seconds, labeled by provider and target.
+	TranslationLatency = "translate_translation_duration_seconds"
+
+	// Requests counts the HTTP requests, labeled by provider, api_type and status ("error" when no response was received).
+	Requests = "translate_http_requests_total"
+
+	// RequestLatency is the duration of the HTTP requests in seconds, labeled by provider and api_type.
+	RequestLatency = "translate_http_request_duration_seconds"
+
+	// Retries counts the HTTP requests sent again after a failure, labeled by provider and api_type.
+	Retries = "translate_retries_total"
+
+	// Fallbacks counts the translations handed to another API type by the sequential and mix Google API types
+	// after a failure, labeled by the provider and api_type that failed.
+	Fallbacks = "translate_fallbacks_total"
+
+	// CacheHits and CacheMisses count the lookups of cached credentials, labeled by provider and cache
+	// (e.g. "edge_token"). Translation cache middlewares may count theirs too, labeled by provider and target.
+	CacheHits   = "translate_cache_hits_total"
+	CacheMisses = "translate_cache_misses_total"
+
+	// BreakerState is 1 while a proxy or API key is left out after failing and 0 once it is used again,
+	// labeled by kind ("proxy" or "api_key") and name (proxy URL or key index), and for keys by provider and api_type.
+	BreakerState = "translate_breaker_state"
+)
+
+// Help describes every measurement, for the adapters exposing them.
+var Help = map[string]string{
+	Translations:       "Translation calls by outcome.",
+	Characters:         "Characters sent for translation.",
+	TranslationLatency: "Duration of the translation calls in seconds.",
+	Requests:           "HTTP requests sent to the providers by status.",
+	RequestLatency:     "Duration of the HTTP requests in seconds.",
+	Retries:            "HTTP requests sent again after a failure.",
+	Fallbacks:          "Translations handed to another API type after a failure.",
+	CacheHits:          "Cache lookups that found an entry.",
+	CacheMisses:        "Cache lookups that found no entry.",
+	BreakerState:       "1 while a proxy or API key is left out after failing, 0 otherwise.",
+}
+
+// Nop discards every measurement.
+type Nop struct{}
+
+func (Nop) Add(name string, value float64, labels Labels)     {}
+func (Nop) Observe(name string, value float64, labels Labels) {}
+func (Nop) Set(name string, value float64, labels Labels)     {}
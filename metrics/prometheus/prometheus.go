@@ -0,0 +1,191 @@
+// Package prometheus collects the go_translate metrics in memory and exposes them in the Prometheus text format.
+//
+//	registry := prometheus.New()
+//	http.Handle("/metrics", registry)
+//	translator, _ := go_translate.NewTranslator(&go_translate.TranslateOptions{Metrics: registry})
+package prometheus
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dinhcanh303/go_translate/metrics"
+)
+
+// DefaultBuckets are the upper bounds of the histogram buckets, in seconds, when New is given none.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Registry implements metrics.Metrics and serves the collected metrics over HTTP in the Prometheus text format.
+// It is safe for concurrent use.
+type Registry struct {
+	buckets []float64
+
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+type kind string
+
+const (
+	counter   kind = "counter"
+	gauge     kind = "gauge"
+	histogram kind = "histogram"
+)
+
+// family holds the series of one metric.
+type family struct {
+	kind   kind
+	series map[string]*series // Keyed by rendered labels
+}
+
+type series struct {
+	labels string
+	value  float64  // Counter or gauge value, histogram sum
+	count  uint64   // Histogram observations
+	counts []uint64 // Histogram observations per bucket, not cumulative
+}
+
+// New creates an empty Registry with the given histogram buckets (default DefaultBuckets).
+func New(buckets ...float64) *Registry {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &Registry{buckets: buckets, families: map[string]*family{}}
+}
+
+// Add implements metrics.Metrics.
+func (r *Registry) Add(name string, value float64, labels metrics.Labels) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.series(name, counter, labels).value += value
+}
+
+// Observe implements metrics.Metrics.
+func (r *Registry) Observe(name string, value float64, labels metrics.Labels) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.series(name, histogram, labels)
+	if s.counts == nil {
+		s.counts = make([]uint64, len(r.buckets))
+	}
+	s.value += value
+	s.count++
+	if i := sort.SearchFloat64s(r.buckets, value); i < len(r.buckets) {
+		s.counts[i]++
+	}
+}
+
+// Set implements metrics.Metrics.
+func (r *Registry) Set(name string, value float64, labels metrics.Labels) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.series(name, gauge, labels).value = value
+}
+
+// series returns the series of name with labels, creating it if needed. A name keeps the kind it was first used with.
+func (r *Registry) series(name string, k kind, labels metrics.Labels) *series {
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{kind: k, series: map[string]*series{}}
+		r.families[name] = f
+	}
+	key := renderLabels(labels)
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: key}
+		f.series[key] = s
+	}
+	return s
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text format, sorted by name and labels.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var b strings.Builder
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := r.families[name]
+		if help, ok := metrics.Help[name]; ok {
+			fmt.Fprintf(&b, "# HELP %s %s\n", name, help)
+		}
+		fmt.Fprintf(&b, "# TYPE %s %s\n", name, f.kind)
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			s := f.series[key]
+			if f.kind != histogram {
+				fmt.Fprintf(&b, "%s%s %s\n", name, braces(s.labels), formatFloat(s.value))
+				continue
+			}
+			var cumulative uint64
+			for i, upper := range r.buckets {
+				cumulative += s.counts[i]
+				fmt.Fprintf(&b, "%s_bucket%s %d\n", name, braces(join(s.labels, `le="`+formatFloat(upper)+`"`)), cumulative)
+			}
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", name, braces(join(s.labels, `le="+Inf"`)), s.count)
+			fmt.Fprintf(&b, "%s_sum%s %s\n", name, braces(s.labels), formatFloat(s.value))
+			fmt.Fprintf(&b, "%s_count%s %d\n", name, braces(s.labels), s.count)
+		}
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// renderLabels renders labels sorted by name, e.g. `provider="google",target="vi"`.
+func renderLabels(labels metrics.Labels) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + `="` + escaper.Replace(labels[name]) + `"`
+	}
+	return strings.Join(parts, ",")
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func join(labels, label string) string {
+	if labels == "" {
+		return label
+	}
+	return labels + "," + label
+}
+
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
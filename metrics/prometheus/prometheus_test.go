@@ -0,0 +1,46 @@
+package prometheus
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dinhcanh303/go_translate/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := New(0.1, 1)
+	registry.Add(metrics.Translations, 1, metrics.Labels{"provider": "google", "outcome": "success"})
+	registry.Add(metrics.Translations, 2, metrics.Labels{"provider": "google", "outcome": "success"})
+	registry.Add(metrics.Translations, 1, metrics.Labels{"provider": `say "hi"\` + "\n", "outcome": "failure"})
+	registry.Set(metrics.BreakerState, 1, metrics.Labels{"kind": "proxy", "name": "http://10.0.0.1:3128"})
+	registry.Set(metrics.BreakerState, 0, metrics.Labels{"kind": "proxy", "name": "http://10.0.0.1:3128"})
+	for _, latency := range []float64{0.05, 0.1, 0.5, 3} {
+		registry.Observe(metrics.RequestLatency, latency, metrics.Labels{"provider": "microsoft"})
+	}
+	registry.Add("custom_total", 1, nil)
+
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, "text/plain; version=0.0.4; charset=utf-8", recorder.Header().Get("Content-Type"))
+	expected := strings.Join([]string{
+		`# TYPE custom_total counter`,
+		`custom_total 1`,
+		`# HELP translate_breaker_state 1 while a proxy or API key is left out after failing, 0 otherwise.`,
+		`# TYPE translate_breaker_state gauge`,
+		`translate_breaker_state{kind="proxy",name="http://10.0.0.1:3128"} 0`,
+		`# HELP translate_http_request_duration_seconds Duration of the HTTP requests in seconds.`,
+		`# TYPE translate_http_request_duration_seconds histogram`,
+		`translate_http_request_duration_seconds_bucket{provider="microsoft",le="0.1"} 2`,
+		`translate_http_request_duration_seconds_bucket{provider="microsoft",le="1"} 3`,
+		`translate_http_request_duration_seconds_bucket{provider="microsoft",le="+Inf"} 4`,
+		`translate_http_request_duration_seconds_sum{provider="microsoft"} 3.65`,
+		`translate_http_request_duration_seconds_count{provider="microsoft"} 4`,
+		`# HELP translate_translations_total Translation calls by outcome.`,
+		`# TYPE translate_translations_total counter`,
+		`translate_translations_total{outcome="failure",provider="say \"hi\"\\\n"} 1`,
+		`translate_translations_total{outcome="success",provider="google"} 3`,
+	}, "\n") + "\n"
+	require.Equal(t, expected, recorder.Body.String())
+}
@@ -0,0 +1,149 @@
+package go_translate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dinhcanh303/go_translate/metrics/prometheus"
+	"github.com/dinhcanh303/go_translate/translatetest"
+	"github.com/stretchr/testify/require"
+)
+
+// exposition returns the metrics of registry in the Prometheus text format.
+func exposition(registry *prometheus.Registry) string {
+	var b strings.Builder
+	registry.WriteTo(&b)
+	return b.String()
+}
+
+func TestMetrics(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls++; calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"translation":"Xin chào","sourceLanguage":"en"}`)
+	}))
+	defer server.Close()
+
+	registry := prometheus.New()
+	translator, err := NewTranslator(&TranslateOptions{
+		GoogleAPIType:   TypePaGtx,
+		GoogleEndpoints: map[GoogleAPIType]string{TypePaGtx: server.URL},
+		MaxRetries:      1,
+		RetryBackoff:    time.Millisecond,
+		Metrics:         registry,
+	})
+	require.Nil(t, err)
+	_, err = translator.TranslateText(context.Background(), []string{"Hello"}, "vi")
+	require.Nil(t, err)
+
+	out := exposition(registry)
+	for _, line := range []string{
+		`translate_http_requests_total{api_type="pa-gtx",provider="google",status="503"} 1`,
+		`translate_http_requests_total{api_type="pa-gtx",provider="google",status="200"} 1`,
+		`translate_retries_total{api_type="pa-gtx",provider="google"} 1`,
+		`translate_http_request_duration_seconds_count{api_type="pa-gtx",provider="google"} 2`,
+		`translate_translations_total{outcome="success",provider="google",target="vi"} 1`,
+		`translate_characters_total{provider="google",target="vi"} 5`,
+		`translate_translation_duration_seconds_count{provider="google",target="vi"} 1`,
+	} {
+		require.Contains(t, out, line+"\n")
+	}
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	registry := prometheus.New()
+	fake := translatetest.NewFake()
+	translator := Chain(fake, MetricsMiddleware(registry, "fake"))
+	_, err := translator.TranslateText(context.Background(), []string{"Xin", "chào"}, "en")
+	require.Nil(t, err)
+	fake.FailNext(fmt.Errorf("boom"))
+	_, err = translator.TranslateText(context.Background(), []string{"Hello"}, "vi")
+	require.NotNil(t, err)
+
+	out := exposition(registry)
+	for _, line := range []string{
+		`translate_translations_total{outcome="success",provider="fake",target="en"} 1`,
+		`translate_translations_total{outcome="failure",provider="fake",target="vi"} 1`,
+		`translate_characters_total{provider="fake",target="en"} 7`,
+		`translate_characters_total{provider="fake",target="vi"} 5`,
+	} {
+		require.Contains(t, out, line+"\n")
+	}
+}
+
+func TestMetricsFallbacks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	endpoints := map[GoogleAPIType]string{}
+	for _, apiType := range GoogleAPITypeSupport {
+		endpoints[apiType] = server.URL
+	}
+	registry := prometheus.New()
+	translator, err := NewTranslator(&TranslateOptions{GoogleAPIType: TypeSequential, GoogleEndpoints: endpoints, Metrics: registry})
+	require.Nil(t, err)
+	_, err = translator.TranslateText(context.Background(), []string{"Hello"}, "vi")
+	require.NotNil(t, err)
+	require.Contains(t, exposition(registry), `translate_fallbacks_total{api_type="pa-gtx",provider="google"} 1`+"\n")
+}
+
+func TestMetricsBreakerState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") == "throttled" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"translation":"Xin chào","sourceLanguage":"en"}`)
+	}))
+	defer server.Close()
+
+	registry := prometheus.New()
+	translator, err := NewTranslator(&TranslateOptions{
+		GoogleAPIType:            TypePaGtx,
+		GoogleAPIKeysTranslatePa: []string{"throttled", "ok"},
+		GoogleEndpoints:          map[GoogleAPIType]string{TypePaGtx: server.URL},
+		Metrics:                  registry,
+	})
+	require.Nil(t, err)
+	_, err = translator.TranslateText(context.Background(), []string{"Hello"}, "vi")
+	require.Nil(t, err)
+	out := exposition(registry)
+	require.Contains(t, out, `translate_breaker_state{api_type="pa-gtx",kind="api_key",name="0",provider="google"} 1`+"\n")
+	require.NotContains(t, out, "throttled")
+
+	dead := deadProxyURL()
+	pool, err := NewProxyPool([]string{dead}, ProxyPoolOptions{MaxFailures: 1, Metrics: registry})
+	require.Nil(t, err)
+	client, err := pool.Client(&http.Client{})
+	require.Nil(t, err)
+	require.NotNil(t, proxiedGet(client, ""))
+	require.Contains(t, exposition(registry), `translate_breaker_state{kind="proxy",name="`+dead+`"} 1`+"\n")
+}
+
+func TestMetricsEdgeTokenCache(t *testing.T) {
+	server := newEdgeServer(t, 10*time.Minute)
+	registry := prometheus.New()
+	translator, err := NewTranslator(&TranslateOptions{
+		Provider:           ProviderMicrosoft,
+		MicrosoftAuthURL:   server.URL + "/auth",
+		MicrosoftEndpoints: map[MicrosoftAPIType]string{TypeEdge: server.URL + "/translate?to="},
+		Metrics:            registry,
+	})
+	require.Nil(t, err)
+	for i := 0; i < 3; i++ {
+		_, err = translator.TranslateText(context.Background(), []string{"Hello"}, "vi")
+		require.Nil(t, err)
+	}
+	out := exposition(registry)
+	require.Contains(t, out, `translate_cache_misses_total{cache="edge_token",provider="microsoft"} 1`+"\n")
+	require.Contains(t, out, `translate_cache_hits_total{cache="edge_token",provider="microsoft"} 2`+"\n")
+}
@@ -0,0 +1,222 @@
+package go_translate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	// OpenAIBaseUrl is the default base URL of the chat completions API.
+	OpenAIBaseUrl = "https://api.openai.com/v1"
+
+	// DefaultOpenAIBatchSize is the number of texts sent per prompt when OpenAIOptions.BatchSize is not set.
+	DefaultOpenAIBatchSize = 20
+
+	// DefaultOpenAIMaxAttempts is the number of prompts tried per batch when OpenAIOptions.MaxAttempts is not set.
+	DefaultOpenAIMaxAttempts = 3
+
+	openAISystemPrompt = "You are a professional translation engine. " +
+		"The user sends a JSON object with a target language and a numbered array of items. " +
+		"Translate the text of every item into the target language, keeping placeholders, HTML tags and line breaks unchanged. " +
+		"Reply with a JSON object only, of the form {\"translations\":[{\"id\":1,\"text\":\"...\"}]}, " +
+		"containing exactly one translation for every input id and nothing else."
+)
+
+// ErrMisalignedResponse is returned when an LLM reply does not contain exactly one translation per input item.
+var ErrMisalignedResponse = errors.New("misaligned translation response")
+
+// OpenAIOptions configures ProviderOpenAI. Set it as TranslateOptions.ProviderOptions.
+type OpenAIOptions struct {
+	// BaseURL is the base URL of the OpenAI compatible server, e.g. "http://localhost:11434/v1" (default OpenAIBaseUrl).
+	BaseURL string
+
+	// APIKey is sent as a bearer token; local servers usually do not need one.
+	APIKey string
+
+	// Model is the model name sent with every request.
+	Model string
+
+	// Tone describes the desired register, e.g. "formal", "informal" or "friendly marketing".
+	Tone string
+
+	// Glossary maps source terms to the translation that must be used for them.
+	Glossary map[string]string
+
+	// Temperature is the sampling temperature (default 0 for deterministic output).
+	Temperature float64
+
+	// BatchSize is the number of texts sent per prompt (default DefaultOpenAIBatchSize).
+	BatchSize int
+
+	// MaxAttempts is the number of prompts tried per batch when replies are misaligned (default DefaultOpenAIMaxAttempts).
+	MaxAttempts int
+}
+
+// ProviderName implements ProviderOptions.
+func (OpenAIOptions) ProviderName() Provider {
+	return ProviderOpenAI
+}
+
+// OpenAITranslateService is a Translator backed by an LLM exposing the OpenAI chat completions protocol.
+type OpenAITranslateService struct {
+	client *http.Client      // HTTP client used for making API requests
+	opts   *TranslateOptions // Options for configuring the translation service
+	openai OpenAIOptions
+}
+
+// NewOpenAITranslateService validates the OpenAIOptions carried by opts and creates the service.
+func NewOpenAITranslateService(client *http.Client, opts *TranslateOptions) (*OpenAITranslateService, error) {
+	openai, _ := GetProviderOptions[OpenAIOptions](opts)
+	if openai.Model == "" {
+		return nil, errors.New("openai requires a model")
+	}
+	if openai.BaseURL == "" {
+		openai.BaseURL = OpenAIBaseUrl
+	}
+	openai.BaseURL = strings.TrimRight(openai.BaseURL, "/")
+	if openai.BatchSize <= 0 {
+		openai.BatchSize = DefaultOpenAIBatchSize
+	}
+	if openai.MaxAttempts <= 0 {
+		openai.MaxAttempts = DefaultOpenAIMaxAttempts
+	}
+	return &OpenAITranslateService{client: client, opts: opts, openai: openai}, nil
+}
+
+// Capabilities reports the features supported through prompting.
+func (o *OpenAITranslateService) Capabilities() Capabilities {
+	return Capabilities{Batch: true, HTML: true, Glossary: true}
+}
+
+// openAIItem is one numbered text in a prompt or a reply.
+type openAIItem struct {
+	ID   int    `json:"id"`
+	Text string `json:"text"`
+}
+
+// TranslateText translates the texts in batches of numbered items, retrying batches whose reply is misaligned.
+func (o *OpenAITranslateService) TranslateText(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+	source := sourceLanguage(detectedLangCode)
+	results := make([]string, 0, len(texts))
+	for _, chunk := range chunkTexts(texts, o.openai.BatchSize, 0) {
+		batch := texts[chunk.start:chunk.end]
+		var translated []string
+		var err error
+		for attempt := 0; attempt < o.openai.MaxAttempts; attempt++ {
+			translated, err = o.translateBatch(ctx, batch, target, source)
+			if err == nil || !errors.Is(err, ErrMisalignedResponse) {
+				break
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, translated...)
+	}
+	return results, nil
+}
+
+func (o *OpenAITranslateService) translateBatch(ctx context.Context, texts []string, target, source string) ([]string, error) {
+	items := make([]openAIItem, len(texts))
+	for i, text := range texts {
+		items[i] = openAIItem{ID: i + 1, Text: text}
+	}
+	prompt := map[string]any{"target_language": target, "items": items}
+	if source != "" {
+		prompt["source_language"] = source
+	}
+	userContent, err := json.Marshal(prompt)
+	if err != nil {
+		return nil, err
+	}
+	payload := map[string]any{
+		"model":       o.openai.Model,
+		"temperature": o.openai.Temperature,
+		"messages": []map[string]string{
+			{"role": "system", "content": o.systemPrompt()},
+			{"role": "user", "content": string(userContent)},
+		},
+		"response_format": map[string]string{"type": "json_object"},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	headers := map[string]string{"Content-Type": "application/json"}
+	if o.openai.APIKey != "" {
+		headers["Authorization"] = "Bearer " + o.openai.APIKey
+	}
+	var completion struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := doRequestJSON(ctx, o.client, o.opts, "POST", o.openai.BaseURL+"/chat/completions", headers, nil, body, &completion); err != nil {
+		return nil, providerError(ProviderOpenAI, err)
+	}
+	if len(completion.Choices) == 0 {
+		return nil, fmt.Errorf("%w: no choices returned", ErrMisalignedResponse)
+	}
+	return parseOpenAITranslations(completion.Choices[0].Message.Content, len(texts))
+}
+
+// systemPrompt adds the tone and glossary instructions to the base prompt.
+func (o *OpenAITranslateService) systemPrompt() string {
+	var b strings.Builder
+	b.WriteString(openAISystemPrompt)
+	if o.openai.Tone != "" {
+		b.WriteString("\nUse a " + o.openai.Tone + " tone.")
+	}
+	if len(o.openai.Glossary) > 0 {
+		terms := make([]string, 0, len(o.openai.Glossary))
+		for term := range o.openai.Glossary {
+			terms = append(terms, term)
+		}
+		sort.Strings(terms)
+		b.WriteString("\nAlways translate these terms exactly as given:")
+		for _, term := range terms {
+			b.WriteString(fmt.Sprintf("\n- %q => %q", term, o.openai.Glossary[term]))
+		}
+	}
+	return b.String()
+}
+
+// parseOpenAITranslations decodes a reply and checks that it holds exactly the ids 1..count.
+// Both {"translations":[...]} and a bare array are accepted, optionally wrapped in a markdown code fence.
+func parseOpenAITranslations(content string, count int) ([]string, error) {
+	content = strings.TrimSpace(content)
+	if strings.HasPrefix(content, "```") {
+		content = strings.TrimPrefix(content, "```json")
+		content = strings.TrimPrefix(content, "```")
+		content = strings.TrimSuffix(content, "```")
+	}
+	var items []openAIItem
+	var wrapped struct {
+		Translations []openAIItem `json:"translations"`
+	}
+	if err := json.Unmarshal([]byte(content), &wrapped); err == nil && wrapped.Translations != nil {
+		items = wrapped.Translations
+	} else if err := json.Unmarshal([]byte(content), &items); err != nil {
+		return nil, fmt.Errorf("%w: reply is not valid JSON: %v", ErrMisalignedResponse, err)
+	}
+	if len(items) != count {
+		return nil, fmt.Errorf("%w: expected %d items, got %d", ErrMisalignedResponse, count, len(items))
+	}
+	translated := make([]string, count)
+	seen := make([]bool, count)
+	for _, item := range items {
+		if item.ID < 1 || item.ID > count || seen[item.ID-1] {
+			return nil, fmt.Errorf("%w: unexpected or duplicate id %d", ErrMisalignedResponse, item.ID)
+		}
+		seen[item.ID-1] = true
+		translated[item.ID-1] = item.Text
+	}
+	return translated, nil
+}
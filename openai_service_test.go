@@ -0,0 +1,81 @@
+package go_translate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAITranslate(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/chat/completions", r.URL.Path)
+		var payload struct {
+			Model    string `json:"model"`
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&payload))
+		require.Equal(t, "llama3", payload.Model)
+		require.Contains(t, payload.Messages[0].Content, "formal tone")
+		require.Contains(t, payload.Messages[0].Content, `"GoTranslate" => "GoTranslate"`)
+		var prompt struct {
+			TargetLanguage string       `json:"target_language"`
+			Items          []openAIItem `json:"items"`
+		}
+		require.Nil(t, json.Unmarshal([]byte(payload.Messages[1].Content), &prompt))
+
+		calls++
+		var items []openAIItem
+		for _, item := range prompt.Items {
+			items = append(items, openAIItem{ID: item.ID, Text: prompt.TargetLanguage + ":" + item.Text})
+		}
+		// The first reply drops an item to exercise the misalignment retry.
+		if calls == 1 {
+			items = items[1:]
+		}
+		content, _ := json.Marshal(map[string]any{"translations": items})
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"role": "assistant", "content": string(content)}}},
+		})
+	}))
+	defer server.Close()
+
+	translator, err := NewTranslator(&TranslateOptions{
+		Provider: ProviderOpenAI,
+		ProviderOptions: OpenAIOptions{
+			BaseURL:  server.URL + "/v1",
+			Model:    "llama3",
+			Tone:     "formal",
+			Glossary: map[string]string{"GoTranslate": "GoTranslate"},
+		},
+	})
+	require.Nil(t, err)
+	result, err := translator.TranslateText(context.Background(), []string{"Hello", "Thanks for using GoTranslate"}, "vi")
+	require.Nil(t, err)
+	require.Equal(t, []string{"vi:Hello", "vi:Thanks for using GoTranslate"}, result)
+	require.Equal(t, 2, calls)
+}
+
+func TestParseOpenAITranslations(t *testing.T) {
+	result, err := parseOpenAITranslations("```json\n[{\"id\":2,\"text\":\"b\"},{\"id\":1,\"text\":\"a\"}]\n```", 2)
+	require.Nil(t, err)
+	require.Equal(t, []string{"a", "b"}, result)
+
+	for _, content := range []string{
+		`{"translations":[{"id":1,"text":"a"},{"id":1,"text":"b"}]}`,
+		`{"translations":[{"id":1,"text":"a"},{"id":3,"text":"b"}]}`,
+		`not json`,
+		`{"translations":[]}`,
+	} {
+		_, err := parseOpenAITranslations(content, 2)
+		require.True(t, errors.Is(err, ErrMisalignedResponse), content)
+	}
+}
@@ -1,6 +1,10 @@
 package go_translate
 
-import "net/http"
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
 
 // TranslateOptions defines configurable options for the translation service.
 type TranslateOptions struct {
@@ -11,25 +15,39 @@ type TranslateOptions struct {
 	//HTTPClient config
 	HTTPClient *http.Client
 
+	// ProxyPool sends the requests through a pool of proxies (default none). The transport of HTTPClient,
+	// if set, must be an *http.Transport; to combine the pool with another transport, leave ProxyPool
+	// unset and use ProxyPool.Transport as the inner transport of HTTPClient.
+	ProxyPool *ProxyPool
+
 	// GoogleAPIType specifies the API type to use for Google Translate (e.g., "html" || "pa-gtx" || "client-gtx" || "client-dict").
 	GoogleAPIType GoogleAPIType
 
 	// MicrosoftAPIType specifies the API type to use for Microsoft Translate (e.g., "edge" || "smart-link" ).
 	MicrosoftAPIType MicrosoftAPIType
 
-	// UseRandomUserAgents enables random selection of User-Agent headers for each request. (Only Google)
+	// UseRandomUserAgents enables random selection of the header profile, User-Agent included, for each request.
 	UseRandomUserAgents bool
 
+	// HeaderProfiles are the browser header sets requests are sent with (default DefaultHeaderProfiles).
+	// The first one is used unless UseRandomUserAgents is set, in which case a random one is picked per request.
+	HeaderProfiles []HeaderProfile
+
 	// UseRandomServiceUrls enables random selection of base service URLs (e.g., multiple Google endpoints).
 	UseRandomServiceUrls bool
 
 	// AddToken indicates whether a token should be added to the request (used for some unofficial Google APIs).
 	AddToken bool
 
+	// TKKSource provides the key of the token added when AddToken is set (default utils.DefaultTKK).
+	// Use NewWebTKKSource to follow the key published on the Google Translate page.
+	TKKSource TKKSource
+
 	// CustomServiceUrls provides a list of service URLs to override the default service urls (used if random is enabled).
 	CustomServiceUrls []string
 
 	// CustomUserAgents provides a list of User-Agent strings to use (used if random is enabled).
+	// They replace the User-Agent of the header profile, whose Sec-Ch-* client hints are then dropped.
 	CustomUserAgents []string
 
 	//API Key endpoint HTML
@@ -40,4 +58,64 @@ type TranslateOptions struct {
 
 	//API Key endpoint Dictionary
 	GoogleAPIKeyTranslateDic string
+
+	// GoogleAPIKeysTranslateHtml, GoogleAPIKeysTranslatePa and GoogleAPIKeysTranslateDic are pools of keys
+	// rotated among instead of the single key of the matching field above.
+	GoogleAPIKeysTranslateHtml []string
+	GoogleAPIKeysTranslatePa   []string
+	GoogleAPIKeysTranslateDic  []string
+
+	// APIKeyRotation selects how the next key of a pool is picked (default RotateRoundRobin).
+	APIKeyRotation KeyRotation
+
+	// APIKeyCooldown is how long a key answered with 403 or 429 is left out of its pool (default DefaultAPIKeyCooldown).
+	// The request is sent again at once with the next key of the pool.
+	APIKeyCooldown time.Duration
+
+	// GoogleEndpoints overrides the endpoint of individual Google API types (default GoogleUrls).
+	// For TypeClientGtx and TypeClientDictChromeEx an absolute URL replaces the random service host and path.
+	GoogleEndpoints map[GoogleAPIType]string
+
+	// MicrosoftEndpoints overrides the endpoint of individual Microsoft API types (default MicrosoftUrls).
+	// The target language is appended to the TypeEdge endpoint.
+	MicrosoftEndpoints map[MicrosoftAPIType]string
+
+	// MicrosoftAuthURL overrides the URL the Edge authorization token is fetched from (default AuthEdgeUrl).
+	MicrosoftAuthURL string
+
+	// Logger receives the logs of the library, every HTTP request included, with provider, api_type, host, status,
+	// attempt and latency attributes (default none, the library is quiet). Texts are never logged.
+	// Add LoggingMiddleware to Middlewares to log every translation as well.
+	Logger *slog.Logger
+
+	// Metrics receives the measurements of every translation, HTTP request, retry, fallback, token cache lookup and API key quarantine (default none).
+	// With Metrics, NewTranslator wraps the provider with MetricsMiddleware to measure the translations, characters and latency by target language.
+	Metrics Metrics
+
+	// Tracer creates a span for every translation, Google API type attempt, token fetch and HTTP request (default none).
+	// With a Tracer, NewTranslator wraps the provider with TracingMiddleware.
+	Tracer Tracer
+
+	// Middlewares decorate the Translator returned by NewTranslator, the first one seeing the call first (default none).
+	// LoggingMiddleware logs the failed translations.
+	Middlewares []Middleware
+
+	// HTTPMiddlewares wrap every HTTP request of the providers, the first one seeing the request first.
+	// Use them for signing, custom headers, logging, metrics or rewriting requests.
+	HTTPMiddlewares []HTTPMiddleware
+
+	// MaxRetries is the number of times a request failing with a throttling (429), server (5xx) or network error is retried.
+	// Retries are done by a RetryMiddleware wrapping HTTPMiddlewares.
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry, doubled on every following attempt up to MaxRetryBackoff (default 500ms).
+	RetryBackoff time.Duration
+
+	// MaxResponseSize limits the size of a decoded response body in bytes (default utils.DefaultMaxResponseSize).
+	// Larger responses fail with utils.ErrResponseTooLarge.
+	MaxResponseSize int64
+
+	// ProviderOptions carries options specific to the selected provider (e.g., credentials of an official API).
+	// Its ProviderName must match Provider.
+	ProviderOptions ProviderOptions
 }
@@ -0,0 +1,132 @@
+package go_translate
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// PapagoBaseUrl is the default endpoint of Papago on Naver Cloud Platform.
+	PapagoBaseUrl = "https://papago.apigw.ntruss.com"
+
+	// PapagoMaxChars is the maximum number of characters sent in one Papago request.
+	PapagoMaxChars = 5000
+)
+
+// PapagoOptions configures ProviderPapago. Set it as TranslateOptions.ProviderOptions.
+type PapagoOptions struct {
+	// ClientID is sent as X-NCP-APIGW-API-KEY-ID.
+	ClientID string
+
+	// ClientSecret is sent as X-NCP-APIGW-API-KEY.
+	ClientSecret string
+
+	// Honorific requests honorific Korean output (only for ko targets).
+	Honorific bool
+
+	// BaseURL overrides the endpoint (e.g., for a test server).
+	BaseURL string
+}
+
+// ProviderName implements ProviderOptions.
+func (PapagoOptions) ProviderName() Provider {
+	return ProviderPapago
+}
+
+// PapagoTranslateService is a Translator and Detector for Naver Papago.
+type PapagoTranslateService struct {
+	client *http.Client      // HTTP client used for making API requests
+	opts   *TranslateOptions // Options for configuring the translation service
+	papago PapagoOptions
+}
+
+// NewPapagoTranslateService validates the PapagoOptions carried by opts and creates the service.
+func NewPapagoTranslateService(client *http.Client, opts *TranslateOptions) (*PapagoTranslateService, error) {
+	papago, _ := GetProviderOptions[PapagoOptions](opts)
+	if papago.ClientID == "" || papago.ClientSecret == "" {
+		return nil, errors.New("papago requires a client ID and a client secret")
+	}
+	if papago.BaseURL == "" {
+		papago.BaseURL = PapagoBaseUrl
+	}
+	papago.BaseURL = strings.TrimRight(papago.BaseURL, "/")
+	return &PapagoTranslateService{client: client, opts: opts, papago: papago}, nil
+}
+
+// Capabilities reports the features supported by Papago.
+func (p *PapagoTranslateService) Capabilities() Capabilities {
+	return Capabilities{Batch: true, Detection: true}
+}
+
+// TranslateText translates the texts with Papago. Papago takes a single text per request,
+// so texts are joined with newlines into requests of at most PapagoMaxChars characters.
+func (p *PapagoTranslateService) TranslateText(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+	source := sourceLanguage(detectedLangCode)
+	if source == "" {
+		source = "auto"
+	} else {
+		source = papagoLanguageCode(source)
+	}
+	target = papagoLanguageCode(target)
+	return translateJoined(ctx, ProviderPapago, texts, PapagoMaxChars, charLength, func(ctx context.Context, joined string) (string, error) {
+		form := url.Values{"source": {source}, "target": {target}, "text": {joined}}
+		if p.papago.Honorific {
+			form.Set("honorific", "true")
+		}
+		var result struct {
+			Message struct {
+				Result struct {
+					TranslatedText string `json:"translatedText"`
+				} `json:"result"`
+			} `json:"message"`
+		}
+		if err := p.call(ctx, "/nmt/v1/translation", form, &result); err != nil {
+			return "", err
+		}
+		return result.Message.Result.TranslatedText, nil
+	})
+}
+
+// DetectLanguage returns the detected language code of every text.
+func (p *PapagoTranslateService) DetectLanguage(ctx context.Context, texts []string) ([]string, error) {
+	languages := make([]string, 0, len(texts))
+	for _, text := range texts {
+		var result struct {
+			LangCode string `json:"langCode"`
+		}
+		if err := p.call(ctx, "/langs/v1/dect", url.Values{"query": {text}}, &result); err != nil {
+			return nil, err
+		}
+		languages = append(languages, result.LangCode)
+	}
+	return languages, nil
+}
+
+func (p *PapagoTranslateService) call(ctx context.Context, path string, form url.Values, out any) error {
+	headers := map[string]string{
+		"Content-Type":           "application/x-www-form-urlencoded; charset=UTF-8",
+		"X-NCP-APIGW-API-KEY-ID": p.papago.ClientID,
+		"X-NCP-APIGW-API-KEY":    p.papago.ClientSecret,
+	}
+	if err := doRequestJSON(ctx, p.client, p.opts, "POST", p.papago.BaseURL+path, headers, nil, []byte(form.Encode()), out); err != nil {
+		return providerError(ProviderPapago, err)
+	}
+	return nil
+}
+
+// papagoLanguageCode maps a language tag to a Papago language code.
+// Papago distinguishes "zh-CN" and "zh-TW" and uses base codes for every other language.
+func papagoLanguageCode(code string) string {
+	base, rest := splitLanguageCode(code)
+	if base != "zh" {
+		return base
+	}
+	switch rest {
+	case "Hant", "TW", "HK", "MO", "Hant-TW", "Hant-HK":
+		return "zh-TW"
+	}
+	return "zh-CN"
+}
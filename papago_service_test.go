@@ -0,0 +1,43 @@
+package go_translate
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPapagoTranslate(t *testing.T) {
+	server := newFixtureServer(t, map[string]fixtureRoute{
+		"/nmt/v1/translation": {fixture: "papago_translate.json", check: func(r *http.Request) {
+			assert.Equal(t, "client-id", r.Header.Get("X-NCP-APIGW-API-KEY-ID"))
+			assert.Equal(t, "client-secret", r.Header.Get("X-NCP-APIGW-API-KEY"))
+			assert.Nil(t, r.ParseForm())
+			assert.Equal(t, "auto", r.PostForm.Get("source"))
+			assert.Equal(t, "ko", r.PostForm.Get("target"))
+			assert.Equal(t, "Hello world\nHow are you?", r.PostForm.Get("text"))
+		}},
+		"/langs/v1/dect": {fixture: "papago_detect.json"},
+	})
+
+	translator, err := NewTranslator(&TranslateOptions{
+		Provider:        ProviderPapago,
+		ProviderOptions: PapagoOptions{ClientID: "client-id", ClientSecret: "client-secret", BaseURL: server.URL},
+	})
+	require.Nil(t, err)
+	result, err := translator.TranslateText(context.Background(), []string{"Hello world", "How are you?"}, "ko")
+	require.Nil(t, err)
+	require.Equal(t, []string{"안녕하세요 세계", "어떻게 지내세요?"}, result)
+
+	languages, err := translator.(Detector).DetectLanguage(context.Background(), []string{"안녕하세요"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"ko"}, languages)
+}
+
+func TestPapagoLanguageCode(t *testing.T) {
+	require.Equal(t, "zh-TW", papagoLanguageCode("zh-Hant"))
+	require.Equal(t, "zh-CN", papagoLanguageCode("zh"))
+	require.Equal(t, "ko", papagoLanguageCode("ko-KR"))
+}
@@ -13,4 +13,31 @@ const (
 
 	//Mix both ProviderGoogle and ProviderMicrosoft
 	ProviderMix Provider = "mix"
+
+	// ProviderGoogleCloud represents the official Google Cloud Translation API (v2 and v3).
+	ProviderGoogleCloud Provider = "google-cloud"
+
+	// ProviderAzure represents the official Azure AI Translator API.
+	ProviderAzure Provider = "azure"
+
+	// ProviderDeepL represents the DeepL API (Free and Pro).
+	ProviderDeepL Provider = "deepl"
+
+	// ProviderLibreTranslate represents a (self-hosted) LibreTranslate instance.
+	ProviderLibreTranslate Provider = "libretranslate"
+
+	// ProviderYandex represents the Yandex Cloud Translate API.
+	ProviderYandex Provider = "yandex"
+
+	// ProviderPapago represents the Naver Papago translation API.
+	ProviderPapago Provider = "papago"
+
+	// ProviderBaidu represents the Baidu Fanyi (百度翻译) general translation API.
+	ProviderBaidu Provider = "baidu"
+
+	// ProviderOpenAI represents an LLM served through the OpenAI chat completions protocol.
+	ProviderOpenAI Provider = "openai"
+
+	// ProviderPseudo represents the offline pseudo-localization provider used for UI testing.
+	ProviderPseudo Provider = "pseudo"
 )
@@ -0,0 +1,126 @@
+package go_translate
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultPseudoExpansion is the length expansion applied when PseudoOptions.ExpansionRatio is not set.
+const DefaultPseudoExpansion = 0.3
+
+// PseudoOptions configures ProviderPseudo. Set it as TranslateOptions.ProviderOptions.
+type PseudoOptions struct {
+	// ExpansionRatio is the extra length added to every text, e.g. 0.3 makes texts 30% longer (default DefaultPseudoExpansion).
+	// Use a negative value to disable expansion.
+	ExpansionRatio float64
+
+	// NoBrackets disables the "[" and "]" markers around every text.
+	NoBrackets bool
+
+	// NoAccents disables replacing letters with accented look-alikes.
+	NoAccents bool
+
+	// RTL wraps every text in right-to-left override marks to test mirrored layouts.
+	RTL bool
+}
+
+// ProviderName implements ProviderOptions.
+func (PseudoOptions) ProviderName() Provider {
+	return ProviderPseudo
+}
+
+// PseudoTranslateService is an offline Translator producing pseudo-localized text,
+// e.g. "Hello {name}" -> "[Ĥéļļö {name} ~~]". Placeholders, HTML tags and entities are kept as is.
+type PseudoTranslateService struct {
+	pseudo PseudoOptions
+}
+
+// NewPseudoTranslateService creates a pseudo-localization service from the PseudoOptions carried by opts.
+func NewPseudoTranslateService(opts *TranslateOptions) *PseudoTranslateService {
+	pseudo, _ := GetProviderOptions[PseudoOptions](opts)
+	if pseudo.ExpansionRatio == 0 {
+		pseudo.ExpansionRatio = DefaultPseudoExpansion
+	}
+	return &PseudoTranslateService{pseudo: pseudo}
+}
+
+// Capabilities reports the features supported by the pseudo-localization provider.
+func (p *PseudoTranslateService) Capabilities() Capabilities {
+	return Capabilities{Batch: true, HTML: true}
+}
+
+// TranslateText pseudo-localizes every text. The target language is ignored.
+func (p *PseudoTranslateService) TranslateText(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	results := make([]string, len(texts))
+	for i, text := range texts {
+		results[i] = p.localize(text)
+	}
+	return results, nil
+}
+
+// pseudoProtected matches the parts of a text that must not be altered:
+// HTML tags, entities, ICU/brace placeholders ({name}, {{name}}, ${name}) and printf verbs (%s, %1$d, %-5.2f, %%).
+// The space flag is not recognized, so that prose such as "100% sure" is not taken for a verb.
+var pseudoProtected = regexp.MustCompile(`<[^>]*>|&[#a-zA-Z0-9]+;|\$?\{\{?[^{}]*\}?\}|%(\d+\$)?[-+#0]*\d*(\.\d+)?[bcdeEfFgGoOpqstTuvxX%]`)
+
+// pseudoAccents maps ASCII letters to accented look-alikes.
+var pseudoAccents = map[rune]rune{
+	'a': 'á', 'b': 'ƀ', 'c': 'ç', 'd': 'ð', 'e': 'é', 'f': 'ƒ', 'g': 'ĝ', 'h': 'ĥ', 'i': 'î', 'j': 'ĵ',
+	'k': 'ķ', 'l': 'ļ', 'm': 'ɱ', 'n': 'ñ', 'o': 'ö', 'p': 'þ', 'q': 'ǫ', 'r': 'ŕ', 's': 'š', 't': 'ţ',
+	'u': 'û', 'v': 'ṽ', 'w': 'ŵ', 'x': 'ẋ', 'y': 'ý', 'z': 'ž',
+	'A': 'Å', 'B': 'Ɓ', 'C': 'Ç', 'D': 'Ð', 'E': 'É', 'F': 'Ƒ', 'G': 'Ĝ', 'H': 'Ĥ', 'I': 'Î', 'J': 'Ĵ',
+	'K': 'Ķ', 'L': 'Ļ', 'M': 'Ṁ', 'N': 'Ñ', 'O': 'Ö', 'P': 'Þ', 'Q': 'Ǫ', 'R': 'Ŕ', 'S': 'Š', 'T': 'Ţ',
+	'U': 'Û', 'V': 'Ṽ', 'W': 'Ŵ', 'X': 'Ẋ', 'Y': 'Ý', 'Z': 'Ž',
+}
+
+const (
+	rtlOverride = "\u202e" // RIGHT-TO-LEFT OVERRIDE
+	popFormat   = "\u202c" // POP DIRECTIONAL FORMATTING
+)
+
+// localize pseudo-localizes the translatable parts of text and adds the expansion padding and markers.
+func (p *PseudoTranslateService) localize(text string) string {
+	var b strings.Builder
+	translatable := 0
+	last := 0
+	for _, loc := range pseudoProtected.FindAllStringIndex(text, -1) {
+		translatable += p.writeSegment(&b, text[last:loc[0]])
+		b.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	translatable += p.writeSegment(&b, text[last:])
+	result := b.String()
+	if p.pseudo.ExpansionRatio > 0 && translatable > 0 {
+		result += " " + strings.Repeat("~", int(math.Ceil(float64(translatable)*p.pseudo.ExpansionRatio)))
+	}
+	if !p.pseudo.NoBrackets {
+		result = "[" + result + "]"
+	}
+	return result
+}
+
+// writeSegment writes a translatable segment and returns its length in runes.
+func (p *PseudoTranslateService) writeSegment(b *strings.Builder, segment string) int {
+	if segment == "" {
+		return 0
+	}
+	if p.pseudo.RTL {
+		b.WriteString(rtlOverride)
+	}
+	for _, r := range segment {
+		if accented, ok := pseudoAccents[r]; ok && !p.pseudo.NoAccents {
+			r = accented
+		}
+		b.WriteRune(r)
+	}
+	if p.pseudo.RTL {
+		b.WriteString(popFormat)
+	}
+	return utf8.RuneCountInString(segment)
+}
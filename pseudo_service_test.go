@@ -0,0 +1,55 @@
+package go_translate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPseudoTranslate(t *testing.T) {
+	tcs := map[string]struct {
+		opts     PseudoOptions
+		input    string
+		expected string
+	}{
+		"accents and expansion": {
+			input:    "Hello",
+			expected: "[Ĥéļļö ~~]",
+		},
+		"placeholders and tags": {
+			opts:     PseudoOptions{ExpansionRatio: -1},
+			input:    `Hi {name}, <b class="x">%d</b> new &amp; ${count}`,
+			expected: `[Ĥî {name}, <b class="x">%d</b> ñéŵ &amp; ${count}]`,
+		},
+		"printf verbs": {
+			opts:     PseudoOptions{ExpansionRatio: -1},
+			input:    "%-5d of %1$s cost %+.2f %%",
+			expected: "[%-5d öƒ %1$s çöšţ %+.2f %%]",
+		},
+		"percent in prose": {
+			opts:     PseudoOptions{ExpansionRatio: -1},
+			input:    "100% sure",
+			expected: "[100% šûŕé]",
+		},
+		"no brackets no accents": {
+			opts:     PseudoOptions{NoBrackets: true, NoAccents: true, ExpansionRatio: 1},
+			input:    "OK",
+			expected: "OK ~~",
+		},
+		"rtl": {
+			opts:     PseudoOptions{RTL: true, ExpansionRatio: -1},
+			input:    "Hi %s",
+			expected: "[\u202eĤî \u202c%s]",
+		},
+	}
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			translator, err := NewTranslator(&TranslateOptions{Provider: ProviderPseudo, ProviderOptions: tc.opts})
+			require.Nil(t, err)
+			result, err := translator.TranslateText(context.Background(), []string{tc.input}, "xx")
+			require.Nil(t, err)
+			require.Equal(t, []string{tc.expected}, result)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package go_translate
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// ProviderFactory builds a Translator for a registered provider from the shared HTTP client and options.
+// The options have already been defaulted by NewTranslator; the factory validates provider-specific fields.
+type ProviderFactory func(client *http.Client, opts *TranslateOptions) (Translator, error)
+
+// ProviderOptions carries options that only make sense for one provider.
+// Implementations are set on TranslateOptions.ProviderOptions and read back by the provider's factory.
+type ProviderOptions interface {
+	// ProviderName returns the provider these options belong to.
+	ProviderName() Provider
+}
+
+// Capabilities describes the optional features a provider supports.
+type Capabilities struct {
+	Batch     bool // Translates several texts in one request
+	Detection bool // Implements Detector
+	HTML      bool // Preserves HTML markup in the input
+	Glossary  bool // Supports user-defined glossaries
+}
+
+// CapabilityReporter is implemented by translators that declare their capabilities.
+type CapabilityReporter interface {
+	Capabilities() Capabilities
+}
+
+// Detector is implemented by providers that can detect the language of a text.
+type Detector interface {
+	// DetectLanguage returns the detected language code for each of the input texts.
+	DetectLanguage(ctx context.Context, texts []string) ([]string, error)
+}
+
+// Language describes a language supported by a provider.
+type Language struct {
+	Code    string   // Language code used by the provider
+	Name    string   // Human readable name
+	Targets []string // Codes this language can be translated into, if the provider reports them
+}
+
+// LanguageLister is implemented by providers that can report their supported languages.
+type LanguageLister interface {
+	Languages(ctx context.Context) ([]Language, error)
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[Provider]ProviderFactory{}
+)
+
+func init() {
+	RegisterProvider(ProviderGoogle, func(client *http.Client, opts *TranslateOptions) (Translator, error) {
+		if err := applyGoogleDefaults(opts); err != nil {
+			return nil, err
+		}
+		return NewGoogleTranslateService(client, opts), nil
+	})
+	RegisterProvider(ProviderMicrosoft, func(client *http.Client, opts *TranslateOptions) (Translator, error) {
+		return NewMicrosoftTranslateService(client, opts), nil
+	})
+	RegisterProvider(ProviderMix, func(client *http.Client, opts *TranslateOptions) (Translator, error) {
+		provider := ProviderGoogle
+		if rand.Intn(2) != 0 {
+			provider = ProviderMicrosoft
+		}
+		factory, _ := lookupProvider(provider)
+		return factory(client, opts)
+	})
+	RegisterProvider(ProviderGoogleCloud, func(client *http.Client, opts *TranslateOptions) (Translator, error) {
+		return NewGoogleCloudTranslateService(client, opts)
+	})
+	RegisterProvider(ProviderAzure, func(client *http.Client, opts *TranslateOptions) (Translator, error) {
+		return NewAzureTranslateService(client, opts)
+	})
+	RegisterProvider(ProviderDeepL, func(client *http.Client, opts *TranslateOptions) (Translator, error) {
+		return NewDeepLTranslateService(client, opts)
+	})
+	RegisterProvider(ProviderLibreTranslate, func(client *http.Client, opts *TranslateOptions) (Translator, error) {
+		return NewLibreTranslateService(client, opts)
+	})
+	RegisterProvider(ProviderYandex, func(client *http.Client, opts *TranslateOptions) (Translator, error) {
+		return NewYandexTranslateService(client, opts)
+	})
+	RegisterProvider(ProviderPapago, func(client *http.Client, opts *TranslateOptions) (Translator, error) {
+		return NewPapagoTranslateService(client, opts)
+	})
+	RegisterProvider(ProviderBaidu, func(client *http.Client, opts *TranslateOptions) (Translator, error) {
+		return NewBaiduTranslateService(client, opts)
+	})
+	RegisterProvider(ProviderOpenAI, func(client *http.Client, opts *TranslateOptions) (Translator, error) {
+		return NewOpenAITranslateService(client, opts)
+	})
+	RegisterProvider(ProviderPseudo, func(client *http.Client, opts *TranslateOptions) (Translator, error) {
+		return NewPseudoTranslateService(opts), nil
+	})
+}
+
+// RegisterProvider makes a provider available to NewTranslator under the given name.
+// Registering a name that already exists replaces the previous factory, which allows overriding built-in providers.
+// It panics if name is empty or factory is nil.
+func RegisterProvider(name Provider, factory ProviderFactory) {
+	if name == "" {
+		panic("go_translate: RegisterProvider with empty provider name")
+	}
+	if factory == nil {
+		panic("go_translate: RegisterProvider factory is nil for provider " + string(name))
+	}
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[name] = factory
+}
+
+// Providers returns the sorted names of all registered providers.
+func Providers() []Provider {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	names := make([]Provider, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+func lookupProvider(name Provider) (ProviderFactory, bool) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	factory, ok := providers[name]
+	return factory, ok
+}
+
+// CapabilitiesOf returns the capabilities declared by t, or the zero value if t does not declare any.
+func CapabilitiesOf(t Translator) Capabilities {
+	if reporter, ok := t.(CapabilityReporter); ok {
+		return reporter.Capabilities()
+	}
+	return Capabilities{}
+}
+
+// GetProviderOptions returns opts.ProviderOptions as T if it has that type.
+func GetProviderOptions[T ProviderOptions](opts *TranslateOptions) (T, bool) {
+	var zero T
+	if opts == nil || opts.ProviderOptions == nil {
+		return zero, false
+	}
+	value, ok := opts.ProviderOptions.(T)
+	return value, ok
+}
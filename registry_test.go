@@ -0,0 +1,73 @@
+package go_translate
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type echoOptions struct {
+	Prefix string
+}
+
+func (echoOptions) ProviderName() Provider { return "echo" }
+
+type echoTranslator struct {
+	prefix string
+}
+
+func (e *echoTranslator) TranslateText(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+	out := make([]string, len(texts))
+	for i, text := range texts {
+		out[i] = e.prefix + text
+	}
+	return out, nil
+}
+
+func (e *echoTranslator) Capabilities() Capabilities {
+	return Capabilities{Batch: true, Glossary: true}
+}
+
+// unregisterProvider removes name from the registry, so that providers registered by a test do not leak into the others.
+func unregisterProvider(name Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	delete(providers, name)
+}
+
+func TestRegisterProvider(t *testing.T) {
+	RegisterProvider("echo", func(client *http.Client, opts *TranslateOptions) (Translator, error) {
+		echoOpts, _ := GetProviderOptions[echoOptions](opts)
+		return &echoTranslator{prefix: echoOpts.Prefix}, nil
+	})
+	t.Cleanup(func() { unregisterProvider("echo") })
+	require.Contains(t, Providers(), Provider("echo"))
+	require.Contains(t, Providers(), ProviderGoogle)
+
+	translator, err := NewTranslator(&TranslateOptions{Provider: "echo", ProviderOptions: echoOptions{Prefix: "vi:"}})
+	require.Nil(t, err)
+	result, err := translator.TranslateText(context.Background(), []string{"hello"}, "vi")
+	require.Nil(t, err)
+	require.Equal(t, []string{"vi:hello"}, result)
+	require.Equal(t, Capabilities{Batch: true, Glossary: true}, CapabilitiesOf(translator))
+
+	_, err = NewTranslator(&TranslateOptions{Provider: ProviderGoogle, ProviderOptions: echoOptions{}})
+	require.NotNil(t, err)
+
+	_, err = NewTranslator(&TranslateOptions{Provider: "unknown"})
+	require.NotNil(t, err)
+
+	unregisterProvider("echo")
+	require.NotContains(t, Providers(), Provider("echo"))
+}
+
+func TestNewTranslatorDefaults(t *testing.T) {
+	translator, err := NewTranslator()
+	require.Nil(t, err)
+	google, ok := translator.(*GoogleTranslateService)
+	require.True(t, ok)
+	require.Equal(t, TypeHtml, google.opts.GoogleAPIType)
+	require.True(t, CapabilitiesOf(translator).HTML)
+}
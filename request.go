@@ -0,0 +1,65 @@
+package go_translate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/dinhcanh303/go_translate/utils"
+)
+
+// doRequest sends an HTTP request for a provider through its middlewares and returns the response body.
+// Requests not tagged with a RequestInfo are tagged with the provider of opts.
+func doRequest(ctx context.Context, client *http.Client, opts *TranslateOptions, method, endpoint string, headers map[string]string, params url.Values, body []byte) ([]byte, error) {
+	return utils.DoRequest(requestDoer(client, opts), tagRequest(ctx, opts), method, endpoint, headers, params, body, requestOptions(opts))
+}
+
+// doRequestJSON sends an HTTP request like doRequest and decodes the JSON response body into out as it is read.
+func doRequestJSON(ctx context.Context, client *http.Client, opts *TranslateOptions, method, endpoint string, headers map[string]string, params url.Values, body []byte, out any) error {
+	return utils.DoRequestJSON(requestDoer(client, opts), tagRequest(ctx, opts), method, endpoint, headers, params, body, out, requestOptions(opts))
+}
+
+// tagRequest tags ctx with the provider of opts unless it already carries a RequestInfo.
+func tagRequest(ctx context.Context, opts *TranslateOptions) context.Context {
+	if _, ok := utils.RequestInfoFrom(ctx); ok {
+		return ctx
+	}
+	return utils.WithRequestInfo(ctx, utils.RequestInfo{Provider: string(opts.Provider)})
+}
+
+func requestOptions(opts *TranslateOptions) utils.RequestOptions {
+	return utils.RequestOptions{MaxResponseSize: opts.MaxResponseSize}
+}
+
+// providerError adds the message found in the JSON body of an HTTP error to the error returned by an official API.
+// The {"error":{"message":...}}, {"error":"..."} and {"message":...} shapes are recognized.
+func providerError(provider Provider, err error) error {
+	var httpErr *utils.HTTPError
+	if !errors.As(err, &httpErr) {
+		return err
+	}
+	var body struct {
+		Message string          `json:"message"`
+		Error   json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(httpErr.Body, &body) != nil {
+		return err
+	}
+	message := body.Message
+	var nested struct {
+		Message string `json:"message"`
+	}
+	var text string
+	if json.Unmarshal(body.Error, &nested) == nil && nested.Message != "" {
+		message = nested.Message
+	} else if json.Unmarshal(body.Error, &text) == nil && text != "" {
+		message = text
+	}
+	if message == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %s: %w", provider, message, err)
+}
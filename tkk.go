@@ -0,0 +1,133 @@
+package go_translate
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/dinhcanh303/go_translate/utils"
+)
+
+const (
+	// GoogleTranslatePageUrl is the page the current TKK is scraped from.
+	GoogleTranslatePageUrl = "https://translate.google.com"
+
+	// DefaultTKKTTL is how long a scraped TKK is cached when NewWebTKKSource is given no ttl.
+	DefaultTKKTTL = time.Hour
+
+	// tkkRetryInterval is how long the embedded key is used after a failed scrape before trying again.
+	tkkRetryInterval = time.Minute
+
+	// tkkScrapeTimeout bounds a scrape, which does not end with the request that started it.
+	tkkScrapeTimeout = 10 * time.Second
+)
+
+// TKKSource provides the key used to compute the "tk" token of the unofficial Google endpoints.
+// Set it as TranslateOptions.TKKSource to replace the embedded utils.DefaultTKK.
+type TKKSource interface {
+	TKK(ctx context.Context) (utils.TKK, error)
+}
+
+// StaticTKK is a TKKSource always returning the same key.
+type StaticTKK utils.TKK
+
+// TKK implements TKKSource.
+func (s StaticTKK) TKK(ctx context.Context) (utils.TKK, error) {
+	return utils.TKK(s), nil
+}
+
+// tkkPattern matches the key in the page source, e.g. tkk:'406398.2087938574' or TKK='406398.2087938574'.
+var tkkPattern = regexp.MustCompile(`(?i)tkk['"]?\s*[:=]\s*['"](\d+\.\d+)['"]`)
+
+// WebTKKSource scrapes the current TKK from the Google Translate page and caches it for TTL.
+// When the page cannot be fetched or holds no key, the caller that ran the scrape gets utils.DefaultTKK with the error,
+// the following ones utils.DefaultTKK alone, and the scrape is retried a minute later.
+// Concurrent callers share a single scrape; while it runs, they get the previous key, or utils.DefaultTKK before the first one.
+// A scrape is not cancelled with the request that started it, it has a timeout of its own instead.
+// It is safe for concurrent use.
+type WebTKKSource struct {
+	client *http.Client
+	url    string
+	ttl    time.Duration
+
+	mu         sync.Mutex
+	tkk        utils.TKK
+	expiry     time.Time
+	refreshing bool // A scrape is in progress
+}
+
+// NewWebTKKSource creates a WebTKKSource fetching pageURL (default GoogleTranslatePageUrl) and caching the key for ttl
+// (default DefaultTKKTTL). Asked by a translator, the page is fetched like its requests: with its HTTP client, proxies,
+// header profile and HTTP middlewares. Asked directly, it is fetched with client.
+func NewWebTKKSource(client *http.Client, pageURL string, ttl time.Duration) *WebTKKSource {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if pageURL == "" {
+		pageURL = GoogleTranslatePageUrl
+	}
+	if ttl <= 0 {
+		ttl = DefaultTKKTTL
+	}
+	return &WebTKKSource{client: client, url: pageURL, ttl: ttl}
+}
+
+// TKK implements TKKSource. The returned key is always usable, even along with an error.
+func (w *WebTKKSource) TKK(ctx context.Context) (utils.TKK, error) {
+	w.mu.Lock()
+	if time.Now().Before(w.expiry) || w.refreshing {
+		tkk := w.tkk
+		w.mu.Unlock()
+		if tkk == (utils.TKK{}) {
+			tkk = utils.DefaultTKK()
+		}
+		return tkk, nil
+	}
+	w.refreshing = true
+	w.mu.Unlock()
+
+	tkk, err := w.scrape(ctx)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.refreshing = false
+	if err != nil {
+		w.tkk, w.expiry = utils.DefaultTKK(), time.Now().Add(tkkRetryInterval)
+		return w.tkk, err
+	}
+	w.tkk, w.expiry = tkk, time.Now().Add(w.ttl)
+	return tkk, nil
+}
+
+func (w *WebTKKSource) scrape(ctx context.Context) (utils.TKK, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tkkScrapeTimeout)
+	defer cancel()
+	fetch, ok := ctx.Value(pageFetcherKey{}).(pageFetcher)
+	if !ok {
+		fetch = func(ctx context.Context, pageURL string) ([]byte, error) {
+			return utils.DoRequest(w.client, ctx, "GET", pageURL, map[string]string{"User-Agent": DefaultUserAgents[0]}, nil, nil)
+		}
+	}
+	page, err := fetch(ctx, w.url)
+	if err != nil {
+		return utils.TKK{}, err
+	}
+	match := tkkPattern.FindSubmatch(page)
+	if match == nil {
+		return utils.TKK{}, errors.New("no TKK found in " + w.url)
+	}
+	return utils.ParseTKK(string(match[1]))
+}
+
+// pageFetcher fetches a page for a TKKSource.
+type pageFetcher func(ctx context.Context, pageURL string) ([]byte, error)
+
+type pageFetcherKey struct{}
+
+// withPageFetcher returns a copy of ctx carrying the pageFetcher of the translator asking for a TKK.
+func withPageFetcher(ctx context.Context, fetch pageFetcher) context.Context {
+	return context.WithValue(ctx, pageFetcherKey{}, fetch)
+}
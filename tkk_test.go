@@ -0,0 +1,210 @@
+package go_translate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dinhcanh303/go_translate/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// tkkPage is a stand-in for the Google Translate page serving the given body.
+func tkkPage(t *testing.T, status int, body string) (*httptest.Server, *atomic.Int32) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &fetches
+}
+
+func TestWebTKKSource(t *testing.T) {
+	ctx := context.Background()
+	scenarios := map[string]struct {
+		status int
+		body   string
+		want   utils.TKK
+		fails  bool // The scrape fails: the first call reports it, the following ones get the cached default key
+	}{
+		"lowercase key": {http.StatusOK, `<script>window.WIZ_global_data={tkk:'445678.1618375593'};</script>`, utils.TKK{445678, 1618375593}, false},
+		"uppercase key": {http.StatusOK, `<script>TKK='445679.1618375594';</script>`, utils.TKK{445679, 1618375594}, false},
+		"no key":        {http.StatusOK, `<html><body>Google Translate</body></html>`, utils.DefaultTKK(), true},
+		"server error":  {http.StatusInternalServerError, ``, utils.DefaultTKK(), true},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			server, fetches := tkkPage(t, scenario.status, scenario.body)
+			source := NewWebTKKSource(server.Client(), server.URL, time.Hour)
+			for i := 0; i < 3; i++ {
+				tkk, err := source.TKK(ctx)
+				if scenario.fails && i == 0 {
+					require.NotNil(t, err)
+				} else {
+					require.Nil(t, err)
+				}
+				require.Equal(t, scenario.want, tkk)
+			}
+			require.Equal(t, int32(1), fetches.Load())
+		})
+	}
+}
+
+func TestWebTKKSourceRefetchesAfterTTL(t *testing.T) {
+	server, fetches := tkkPage(t, http.StatusOK, `tkk:'445678.1618375593'`)
+	source := NewWebTKKSource(server.Client(), server.URL, 20*time.Millisecond)
+	ctx := context.Background()
+
+	_, err := source.TKK(ctx)
+	require.Nil(t, err)
+	time.Sleep(30 * time.Millisecond)
+	tkk, err := source.TKK(ctx)
+	require.Nil(t, err)
+	require.Equal(t, utils.TKK{445678, 1618375593}, tkk)
+	require.Equal(t, int32(2), fetches.Load())
+}
+
+func TestWebTKKSourceServesStaleKeyWhileScraping(t *testing.T) {
+	release := make(chan struct{})
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := fetches.Add(1)
+		if n > 1 {
+			<-release
+		}
+		fmt.Fprintf(w, "tkk:'%d.1618375593'", 445677+n)
+	}))
+	defer server.Close()
+	source := NewWebTKKSource(server.Client(), server.URL, 20*time.Millisecond)
+	ctx := context.Background()
+
+	first, err := source.TKK(ctx)
+	require.Nil(t, err)
+	time.Sleep(30 * time.Millisecond)
+	refreshed := make(chan utils.TKK)
+	go func() {
+		tkk, _ := source.TKK(ctx)
+		refreshed <- tkk
+	}()
+	require.Eventually(t, func() bool { return fetches.Load() == 2 }, time.Second, time.Millisecond)
+
+	// The scrape is blocked: the other callers get the previous key at once.
+	for i := 0; i < 3; i++ {
+		stale, err := source.TKK(ctx)
+		require.Nil(t, err)
+		require.Equal(t, first, stale)
+	}
+	close(release)
+	require.Equal(t, utils.TKK{445679, 1618375593}, <-refreshed)
+	require.Equal(t, int32(2), fetches.Load())
+}
+
+func TestWebTKKSourceOutlivesCancelledCaller(t *testing.T) {
+	server, fetches := tkkPage(t, http.StatusOK, `tkk:'445678.1618375593'`)
+	source := NewWebTKKSource(server.Client(), server.URL, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The first caller gave up, but the key is scraped and cached for everyone.
+	tkk, err := source.TKK(ctx)
+	require.Nil(t, err)
+	require.Equal(t, utils.TKK{445678, 1618375593}, tkk)
+	tkk, err = source.TKK(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, utils.TKK{445678, 1618375593}, tkk)
+	require.Equal(t, int32(1), fetches.Load())
+}
+
+func TestWebTKKSourceUsesTranslatorStack(t *testing.T) {
+	scenarios := map[string]struct {
+		status int
+		warned bool
+	}{
+		"key scraped":   {http.StatusOK, false},
+		"scrape failed": {http.StatusServiceUnavailable, true},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			var userAgent atomic.Value
+			page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userAgent.Store(r.Header.Get("User-Agent"))
+				w.WriteHeader(scenario.status)
+				fmt.Fprint(w, `tkk:'445678.1618375593'`)
+			}))
+			defer page.Close()
+			api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[[["Xin chào","Hello",null,null,10]],null,"en"]`)
+			}))
+			defer api.Close()
+
+			var seen []string
+			var buf bytes.Buffer
+			translator, err := NewTranslator(&TranslateOptions{
+				GoogleAPIType:   TypeClientGtx,
+				AddToken:        true,
+				TKKSource:       NewWebTKKSource(nil, page.URL, time.Hour),
+				GoogleEndpoints: map[GoogleAPIType]string{TypeClientGtx: api.URL + "/translate_a/single"},
+				HeaderProfiles:  []HeaderProfile{{Name: "test", Headers: map[string]string{"User-Agent": "test-agent"}}},
+				HTTPMiddlewares: []HTTPMiddleware{recordingMiddleware("mw", &seen)},
+				Logger:          slog.New(slog.NewJSONHandler(&buf, nil)),
+			})
+			require.Nil(t, err)
+			_, err = translator.TranslateText(context.Background(), []string{"Hello"}, "vi")
+			require.Nil(t, err)
+
+			require.Equal(t, "test-agent", userAgent.Load())
+			require.Len(t, seen, 4, "the page and the API requests both go through the HTTP middlewares")
+			require.Equal(t, scenario.warned, strings.Contains(buf.String(), "TKK source failed"))
+		})
+	}
+}
+
+// failingTKK is a TKKSource that always fails.
+type failingTKK struct{}
+
+func (failingTKK) TKK(ctx context.Context) (utils.TKK, error) {
+	return utils.TKK{}, errors.New("no key")
+}
+
+func TestTKKSourceIsUsedForToken(t *testing.T) {
+	custom := utils.TKK{445678, 1618375593}
+	scenarios := map[string]struct {
+		source TKKSource
+		want   string
+	}{
+		"default":        {nil, utils.GgTokenGenerate("Hello", utils.DefaultTKK())},
+		"custom source":  {StaticTKK(custom), utils.GgTokenGenerate("Hello", custom)},
+		"failing source": {failingTKK{}, utils.GgTokenGenerate("Hello", utils.DefaultTKK())},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			var tk string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tk = r.URL.Query().Get("tk")
+				fmt.Fprint(w, `[[["Xin chào","Hello",null,null,10]],null,"en"]`)
+			}))
+			defer server.Close()
+
+			translator, err := NewTranslator(&TranslateOptions{
+				GoogleAPIType:   TypeClientGtx,
+				AddToken:        true,
+				TKKSource:       scenario.source,
+				GoogleEndpoints: map[GoogleAPIType]string{TypeClientGtx: server.URL + "/translate_a/single"},
+			})
+			require.Nil(t, err)
+			_, err = translator.TranslateText(context.Background(), []string{"Hello"}, "vi")
+			require.Nil(t, err)
+			require.Equal(t, scenario.want, tk)
+		})
+	}
+}
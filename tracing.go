@@ -0,0 +1,84 @@
+package go_translate
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/dinhcanh303/go_translate/tracing"
+	"github.com/dinhcanh303/go_translate/utils"
+)
+
+// Tracer creates the spans of the library, named and attributed as documented in the tracing package.
+// The tracing/otel package adapts an OpenTelemetry tracer.
+type Tracer = tracing.Tracer
+
+// tracerOf returns the tracer of opts, or one creating no spans.
+func tracerOf(opts *TranslateOptions) Tracer {
+	if opts == nil || opts.Tracer == nil {
+		return tracing.Nop{}
+	}
+	return opts.Tracer
+}
+
+// endSpan records err, if any, on span and ends it.
+func endSpan(span tracing.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// TracingHTTPMiddleware wraps every HTTP request attempt in a tracing.HTTPRequest span, passed to the next
+// middlewares through the request context. Responses with a status of 400 or more are recorded as errors.
+// It is installed inside the RetryMiddleware when TranslateOptions.Tracer is set.
+func TracingHTTPMiddleware(tracer Tracer) HTTPMiddleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			info, _ := utils.RequestInfoFrom(req.Context())
+			ctx, span := tracer.Start(req.Context(), tracing.HTTPRequest,
+				tracing.String(tracing.Provider, info.Provider),
+				tracing.String(tracing.APIType, info.APIType),
+				tracing.String(tracing.Method, req.Method),
+				tracing.String(tracing.Host, req.URL.Host),
+				tracing.Int(tracing.ResendCount, attemptFrom(req.Context())-1),
+			)
+			resp, err := next.Do(req.WithContext(ctx))
+			if err == nil {
+				span.SetAttributes(tracing.Int(tracing.Status, resp.StatusCode))
+				if resp.StatusCode >= 400 {
+					span.RecordError(errors.New("unexpected status " + resp.Status))
+				}
+			}
+			endSpan(span, err)
+			return resp, err
+		})
+	}
+}
+
+// TracingMiddleware wraps every translation in a tracing.Translate span, parent of the spans of the API attempts,
+// token fetches and HTTP requests made for it. NewTranslator installs it first when TranslateOptions.Tracer is set.
+func TracingMiddleware(tracer Tracer, provider string) Middleware {
+	return func(next Translator) Translator {
+		return TranslatorFunc(func(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+			characters := 0
+			for _, text := range texts {
+				characters += utf8.RuneCountInString(text)
+			}
+			attrs := []tracing.Attribute{
+				tracing.String(tracing.Provider, provider),
+				tracing.String(tracing.Target, target),
+				tracing.Int(tracing.Texts, len(texts)),
+				tracing.Int(tracing.Characters, characters),
+			}
+			if len(detectedLangCode) > 0 && detectedLangCode[0] != "" {
+				attrs = append(attrs, tracing.String(tracing.Source, detectedLangCode[0]))
+			}
+			ctx, span := tracer.Start(ctx, tracing.Translate, attrs...)
+			translated, err := next.TranslateText(ctx, texts, target, detectedLangCode...)
+			endSpan(span, err)
+			return translated, err
+		})
+	}
+}
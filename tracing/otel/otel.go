@@ -0,0 +1,74 @@
+// Package otel creates the go_translate spans with an OpenTelemetry tracer. It is a module of its own,
+// so that the core module does not depend on OpenTelemetry.
+//
+//	// import translateotel "github.com/dinhcanh303/go_translate/tracing/otel"
+//	tracer := translateotel.New(otel.Tracer("github.com/dinhcanh303/go_translate"))
+//	translator, _ := go_translate.NewTranslator(&go_translate.TranslateOptions{Tracer: tracer})
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dinhcanh303/go_translate/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// New returns a tracing.Tracer starting its spans with tracer.
+// HTTP request spans are client spans, the others internal spans.
+func New(tracer trace.Tracer) tracing.Tracer {
+	return &otelTracer{tracer: tracer}
+}
+
+type otelTracer struct {
+	tracer trace.Tracer
+}
+
+// Start implements tracing.Tracer.
+func (t *otelTracer) Start(ctx context.Context, name string, attrs ...tracing.Attribute) (context.Context, tracing.Span) {
+	kind := trace.SpanKindInternal
+	if name == tracing.HTTPRequest {
+		kind = trace.SpanKindClient
+	}
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(convert(attrs)...))
+	return ctx, otelSpan{span}
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+// SetAttributes implements tracing.Span.
+func (s otelSpan) SetAttributes(attrs ...tracing.Attribute) {
+	s.span.SetAttributes(convert(attrs)...)
+}
+
+// RecordError implements tracing.Span and sets the status of the span to codes.Error.
+func (s otelSpan) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End implements tracing.Span.
+func (s otelSpan) End() {
+	s.span.End()
+}
+
+func convert(attrs []tracing.Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, len(attrs))
+	for i, attr := range attrs {
+		switch value := attr.Value.(type) {
+		case string:
+			kvs[i] = attribute.String(attr.Key, value)
+		case int:
+			kvs[i] = attribute.Int(attr.Key, value)
+		case bool:
+			kvs[i] = attribute.Bool(attr.Key, value)
+		default:
+			kvs[i] = attribute.String(attr.Key, fmt.Sprint(value))
+		}
+	}
+	return kvs
+}
@@ -0,0 +1,43 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dinhcanh303/go_translate/tracing"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := New(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test"))
+
+	ctx, root := tracer.Start(context.Background(), tracing.Translate, tracing.String(tracing.Target, "vi"), tracing.Int(tracing.Texts, 2))
+	_, request := tracer.Start(ctx, tracing.HTTPRequest, tracing.Bool("cached", false))
+	request.SetAttributes(tracing.Int(tracing.Status, 503))
+	request.RecordError(errors.New("unexpected status 503 Service Unavailable"))
+	request.End()
+	root.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	child, parent := spans[0], spans[1]
+	require.Equal(t, tracing.Translate, parent.Name())
+	require.Equal(t, trace.SpanKindInternal, parent.SpanKind())
+	require.Equal(t, []attribute.KeyValue{attribute.String(tracing.Target, "vi"), attribute.Int(tracing.Texts, 2)}, parent.Attributes())
+	require.Equal(t, codes.Unset, parent.Status().Code)
+
+	require.Equal(t, tracing.HTTPRequest, child.Name())
+	require.Equal(t, trace.SpanKindClient, child.SpanKind())
+	require.Equal(t, parent.SpanContext().SpanID(), child.Parent().SpanID())
+	require.Equal(t, []attribute.KeyValue{attribute.Bool("cached", false), attribute.Int(tracing.Status, 503)}, child.Attributes())
+	require.Equal(t, codes.Error, child.Status().Code)
+	require.Len(t, child.Events(), 1)
+	require.Equal(t, "exception", child.Events()[0].Name)
+}
@@ -0,0 +1,88 @@
+// Package tracing defines the spans created by go_translate and the interface creating them.
+// The otel subpackage adapts an OpenTelemetry tracer; this package has no third-party dependencies.
+package tracing
+
+import "context"
+
+// Attribute is a key-value pair describing a span. Value is a string, int or bool.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a string Attribute.
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int returns an int Attribute.
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Bool returns a bool Attribute.
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Tracer creates the spans of the library. Implementations must be safe for concurrent use.
+type Tracer interface {
+	// Start starts the span name as a child of the span carried by ctx, if any, and returns a context carrying it.
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is an operation started by a Tracer.
+type Span interface {
+	// SetAttributes adds attrs to the span.
+	SetAttributes(attrs ...Attribute)
+
+	// RecordError records err and marks the span as failed.
+	RecordError(err error)
+
+	// End completes the span.
+	End()
+}
+
+// Names of the spans created by the library.
+const (
+	// Translate covers a TranslateText call, with the provider, target, texts and characters attributes.
+	Translate = "translate"
+
+	// APIAttempt covers one API type tried by the sequential and mix Google API types, with the provider and api_type attributes.
+	APIAttempt = "translate.api_attempt"
+
+	// TokenFetch covers the fetch of a credential such as the Edge token or the TKK, with the provider and token attributes.
+	TokenFetch = "translate.token_fetch"
+
+	// HTTPRequest covers one attempt of an HTTP request, with the provider, api_type, method, host, resend count and status attributes.
+	HTTPRequest = "translate.http_request"
+)
+
+// Keys of the span attributes, following the OpenTelemetry semantic conventions for HTTP.
+const (
+	Provider    = "translate.provider"
+	APIType     = "translate.api_type"
+	Target      = "translate.target"
+	Source      = "translate.source"
+	Texts       = "translate.texts"
+	Characters  = "translate.characters"
+	Token       = "translate.token"
+	ResendCount = "http.request.resend_count"
+	Method      = "http.request.method"
+	Host        = "server.address"
+	Status      = "http.response.status_code"
+)
+
+// Nop creates spans doing nothing.
+type Nop struct{}
+
+// Start returns ctx and a span doing nothing.
+func (Nop) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttributes(attrs ...Attribute) {}
+func (nopSpan) RecordError(err error)            {}
+func (nopSpan) End()                             {}
@@ -0,0 +1,180 @@
+package go_translate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dinhcanh303/go_translate/tracing"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTracer records the spans it starts, linked to the span carried by the context they were started with.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+type recordedSpan struct {
+	name   string
+	parent *recordedSpan
+	attrs  map[string]any
+	err    error
+	ended  bool
+}
+
+type spanKey struct{}
+
+func (r *recordingTracer) Start(ctx context.Context, name string, attrs ...tracing.Attribute) (context.Context, tracing.Span) {
+	span := &recordedSpan{name: name, attrs: map[string]any{}}
+	span.parent, _ = ctx.Value(spanKey{}).(*recordedSpan)
+	span.SetAttributes(attrs...)
+	r.mu.Lock()
+	r.spans = append(r.spans, span)
+	r.mu.Unlock()
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...tracing.Attribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) { s.err = err }
+func (s *recordedSpan) End()                  { s.ended = true }
+
+// named returns the spans named name.
+func (r *recordingTracer) named(name string) []*recordedSpan {
+	var spans []*recordedSpan
+	for _, span := range r.spans {
+		if span.name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func TestTracing(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls++; calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"translation":"Xin chào","sourceLanguage":"en"}`)
+	}))
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	translator, err := NewTranslator(&TranslateOptions{
+		GoogleAPIType:   TypePaGtx,
+		GoogleEndpoints: map[GoogleAPIType]string{TypePaGtx: server.URL},
+		MaxRetries:      1,
+		RetryBackoff:    time.Millisecond,
+		Tracer:          tracer,
+	})
+	require.Nil(t, err)
+	_, err = translator.TranslateText(context.Background(), []string{"Hello", "World"}, "vi", "en")
+	require.Nil(t, err)
+
+	require.Len(t, tracer.spans, 3)
+	root := tracer.spans[0]
+	require.Equal(t, tracing.Translate, root.name)
+	require.Nil(t, root.parent)
+	require.Equal(t, map[string]any{
+		tracing.Provider: "google", tracing.Target: "vi", tracing.Source: "en", tracing.Texts: 2, tracing.Characters: 10,
+	}, root.attrs)
+	for i, span := range tracer.named(tracing.HTTPRequest) {
+		require.Same(t, root, span.parent)
+		require.Equal(t, i, span.attrs[tracing.ResendCount])
+		require.Equal(t, "pa-gtx", span.attrs[tracing.APIType])
+		require.Equal(t, "GET", span.attrs[tracing.Method])
+		require.Equal(t, []int{503, 200}[i], span.attrs[tracing.Status])
+		require.Equal(t, i == 0, span.err != nil)
+	}
+	for _, span := range tracer.spans {
+		require.True(t, span.ended, span.name)
+	}
+}
+
+func TestTracingSequentialAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	endpoints := map[GoogleAPIType]string{}
+	for _, apiType := range GoogleAPITypeSupport {
+		endpoints[apiType] = server.URL
+	}
+	tracer := &recordingTracer{}
+	translator, err := NewTranslator(&TranslateOptions{
+		GoogleAPIType:   TypeSequential,
+		GoogleEndpoints: endpoints,
+		AddToken:        true,
+		TKKSource:       failingTKK{},
+		Tracer:          tracer,
+	})
+	require.Nil(t, err)
+	_, err = translator.TranslateText(context.Background(), []string{"Hello"}, "vi")
+	require.NotNil(t, err)
+
+	root := tracer.named(tracing.Translate)[0]
+	require.NotNil(t, root.err)
+	attempts := tracer.named(tracing.APIAttempt)
+	require.NotEmpty(t, attempts)
+	apiTypes := map[any]bool{}
+	for _, attempt := range attempts {
+		require.Same(t, root, attempt.parent)
+		require.NotNil(t, attempt.err)
+		apiTypes[attempt.attrs[tracing.APIType]] = true
+	}
+	require.Len(t, apiTypes, len(attempts))
+	for _, span := range tracer.named(tracing.HTTPRequest) {
+		require.Equal(t, tracing.APIAttempt, span.parent.name)
+	}
+	tokens := tracer.named(tracing.TokenFetch)
+	require.NotEmpty(t, tokens)
+	for _, span := range tokens {
+		require.Equal(t, tracing.APIAttempt, span.parent.name)
+		require.Equal(t, "tkk", span.attrs[tracing.Token])
+		require.NotNil(t, span.err)
+	}
+}
+
+func TestTracingEdgeToken(t *testing.T) {
+	server := newEdgeServer(t, 10*time.Minute)
+	tracer := &recordingTracer{}
+	translator, err := NewTranslator(&TranslateOptions{
+		Provider:           ProviderMicrosoft,
+		MicrosoftAuthURL:   server.URL + "/auth",
+		MicrosoftEndpoints: map[MicrosoftAPIType]string{TypeEdge: server.URL + "/translate?to="},
+		Tracer:             tracer,
+	})
+	require.Nil(t, err)
+	_, err = translator.TranslateText(context.Background(), []string{"Hello"}, "vi")
+	require.Nil(t, err)
+
+	names := make([]string, len(tracer.spans))
+	for i, span := range tracer.spans {
+		names[i] = span.name
+	}
+	require.Equal(t, []string{tracing.Translate, tracing.TokenFetch, tracing.HTTPRequest, tracing.HTTPRequest}, names)
+	root, token := tracer.spans[0], tracer.spans[1]
+	require.Equal(t, "microsoft", token.attrs[tracing.Provider])
+	require.Equal(t, "edge_token", token.attrs[tracing.Token])
+	require.Same(t, root, token.parent)
+	require.Same(t, token, tracer.spans[2].parent)
+	require.Same(t, root, tracer.spans[3].parent)
+}
+
+func TestTracingKeepsServiceReachable(t *testing.T) {
+	translator, err := NewTranslator(&TranslateOptions{Tracer: tracing.Nop{}})
+	require.Nil(t, err)
+	_, ok := translator.(interface{ Unwrap() Translator }).Unwrap().(*GoogleTranslateService)
+	require.True(t, ok)
+}
@@ -5,7 +5,6 @@ package go_translate
 import (
 	"context"
 	"errors"
-	"math/rand"
 	"net/http"
 	"time"
 )
@@ -23,7 +22,8 @@ type Translator interface {
 // NewTranslator returns a Translator implementation based on the given TranslateOptions.
 //
 // If no options are provided, it defaults to using Google Translate with HTML API type.
-// This function panics if the provider is unsupported.
+// The provider is looked up in the registry filled by RegisterProvider; an error is returned if it is unknown.
+// With TranslateOptions.Middlewares, TranslateOptions.Metrics or TranslateOptions.Tracer, the provider is wrapped with Chain.
 func NewTranslator(opts ...*TranslateOptions) (Translator, error) {
 	options, err := validateOptions(opts...)
 	if err != nil {
@@ -33,24 +33,31 @@ func NewTranslator(opts ...*TranslateOptions) (Translator, error) {
 	if options.HTTPClient != nil {
 		client = options.HTTPClient
 	}
-	// Create appropriate service based on provider
-	switch options.Provider {
-	case ProviderGoogle:
-		return NewGoogleTranslateService(client, options), nil
-	case ProviderMicrosoft:
-		return NewMicrosoftTranslateService(client, options), nil
-	case ProviderMix:
-		if rand.Intn(2) == 0 {
-			return NewGoogleTranslateService(client, options), nil
-		} else {
-			return NewMicrosoftTranslateService(client, options), nil
+	if options.ProxyPool != nil {
+		if client, err = options.ProxyPool.Client(client); err != nil {
+			return nil, err
 		}
-	default:
+	}
+	factory, ok := lookupProvider(options.Provider)
+	if !ok {
 		return nil, errors.New("unsupported provider: " + string(options.Provider))
 	}
+	translator, err := factory(client, options)
+	middlewares := options.Middlewares
+	if options.Metrics != nil {
+		middlewares = append([]Middleware{MetricsMiddleware(options.Metrics, string(options.Provider))}, middlewares...)
+	}
+	if options.Tracer != nil {
+		middlewares = append([]Middleware{TracingMiddleware(options.Tracer, string(options.Provider))}, middlewares...)
+	}
+	if err != nil || len(middlewares) == 0 {
+		return translator, err
+	}
+	return Chain(translator, middlewares...), nil
 }
+
 func validateOptions(opts ...*TranslateOptions) (*TranslateOptions, error) {
-	var options *TranslateOptions
+	options := &TranslateOptions{}
 	if len(opts) > 0 && opts[0] != nil {
 		options = opts[0]
 	}
@@ -58,25 +65,30 @@ func validateOptions(opts ...*TranslateOptions) (*TranslateOptions, error) {
 	if options.Provider == "" {
 		options.Provider = ProviderGoogle
 	}
-	if options.Provider == ProviderGoogle {
-		if options.GoogleAPIType == "" {
-			options.GoogleAPIType = TypeHtml
-		}
-		if options.GoogleAPIKeyTranslateHtml == "" {
-			options.GoogleAPIKeyTranslateHtml = GOOGLE_API_KEY_TRANSLATE_HTML
-		}
-		if options.GoogleAPIKeyTranslatePa == "" {
-			options.GoogleAPIKeyTranslatePa = GOOGLE_API_KEY_TRANSLATE_PA
-		}
-		if options.GoogleAPIKeyTranslateDic == "" {
-			options.GoogleAPIKeyTranslateDic = GOOGLE_API_KEY_TRANSLATE_DIC
-		}
-		// Set default GoogleAPIType
-		validTypes := MpGoogleAPITypeSupport
-		if _, ok := validTypes[options.GoogleAPIType]; !ok {
-			return nil, errors.New("unsupported Google API Type, please check list supported")
-		}
+	if options.ProviderOptions != nil && options.ProviderOptions.ProviderName() != options.Provider {
+		return nil, errors.New("provider options for " + string(options.ProviderOptions.ProviderName()) + " cannot be used with provider " + string(options.Provider))
 	}
-
 	return options, nil
 }
+
+// applyGoogleDefaults fills the Google specific options and validates the API type.
+func applyGoogleDefaults(options *TranslateOptions) error {
+	if options.GoogleAPIType == "" {
+		options.GoogleAPIType = TypeHtml
+	}
+	if options.GoogleAPIKeyTranslateHtml == "" {
+		options.GoogleAPIKeyTranslateHtml = GOOGLE_API_KEY_TRANSLATE_HTML
+	}
+	if options.GoogleAPIKeyTranslatePa == "" {
+		options.GoogleAPIKeyTranslatePa = GOOGLE_API_KEY_TRANSLATE_PA
+	}
+	if options.GoogleAPIKeyTranslateDic == "" {
+		options.GoogleAPIKeyTranslateDic = GOOGLE_API_KEY_TRANSLATE_DIC
+	}
+	// Set default GoogleAPIType
+	validTypes := MpGoogleAPITypeSupport
+	if _, ok := validTypes[options.GoogleAPIType]; !ok {
+		return errors.New("unsupported Google API Type, please check list supported")
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package go_translate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dinhcanh303/go_translate/utils"
+)
+
+// textLength measures texts in the unit of the request limit of a provider.
+type textLength struct {
+	unit string
+	of   func(text string) int
+}
+
+var (
+	charLength = textLength{unit: "characters", of: utf8.RuneCountInString}
+	byteLength = textLength{unit: "bytes", of: func(text string) int { return len(text) }}
+)
+
+// translateJoined translates texts with a provider that accepts a single string per request, such as Baidu and Papago.
+// Texts are joined with newlines into chunks of at most maxLen, measured by length, and the result is split back.
+// A text that itself contains a newline is sent on its own and its result kept whole.
+// A text longer than maxLen is rejected before anything is sent.
+func translateJoined(ctx context.Context, provider Provider, texts []string, maxLen int, length textLength, call func(ctx context.Context, joined string) (string, error)) ([]string, error) {
+	for i, text := range texts {
+		if n := length.of(text); n > maxLen {
+			return nil, fmt.Errorf("%s: text %d has %d %s, the limit per request is %d", provider, i, n, length.unit, maxLen)
+		}
+	}
+	results := make([]string, 0, len(texts))
+	start := 0
+	send := func(chunk []string) error {
+		translated, err := call(ctx, utils.JoinWithSeparator(chunk))
+		if err != nil {
+			return err
+		}
+		lines, err := checkTranslationCount(chunk, utils.SplitWithSeparator(translated))
+		if err != nil {
+			return err
+		}
+		results = append(results, lines...)
+		return nil
+	}
+	// flush sends texts[start:end] in chunks whose joined length, separators included, fits in maxLen.
+	flush := func(end int) error {
+		joined := 0
+		for i := start; i < end; i++ {
+			n := length.of(texts[i])
+			if i > start && joined+1+n > maxLen {
+				if err := send(texts[start:i]); err != nil {
+					return err
+				}
+				start, joined = i, 0
+			}
+			if i > start {
+				joined++ // Newline separator, one character and one byte
+			}
+			joined += n
+		}
+		if end > start {
+			return send(texts[start:end])
+		}
+		return nil
+	}
+	for i, text := range texts {
+		if !strings.Contains(text, "\n") {
+			continue
+		}
+		if err := flush(i); err != nil {
+			return nil, err
+		}
+		translated, err := call(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, translated)
+		start = i + 1
+	}
+	if err := flush(len(texts)); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,68 @@
+package go_translate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateJoined(t *testing.T) {
+	tcs := map[string]struct {
+		texts  []string
+		maxLen int
+		length textLength
+		sent   []string
+		err    string
+	}{
+		"joined within the limit": {
+			texts:  []string{"Hello", "World", "Bye"},
+			maxLen: 11,
+			length: charLength,
+			sent:   []string{"Hello\nWorld", "Bye"},
+		},
+		"multi-line text sent alone": {
+			texts:  []string{"Hello", "Two\nlines", "Bye"},
+			maxLen: 100,
+			length: charLength,
+			sent:   []string{"Hello", "Two\nlines", "Bye"},
+		},
+		"oversized text rejected before sending": {
+			texts:  []string{"Hello", strings.Repeat("a", 12)},
+			maxLen: 11,
+			length: charLength,
+			err:    "papago: text 1 has 12 characters, the limit per request is 11",
+		},
+		"4-byte runes counted in bytes": {
+			texts:  []string{"😀😀", "😀", "ab"},
+			maxLen: 9,
+			length: byteLength,
+			sent:   []string{"😀😀", "😀\nab"},
+		},
+		"oversized text in bytes": {
+			texts:  []string{"😀😀😀"},
+			maxLen: 9,
+			length: byteLength,
+			err:    "papago: text 0 has 12 bytes, the limit per request is 9",
+		},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			var sent []string
+			result, err := translateJoined(context.Background(), ProviderPapago, tc.texts, tc.maxLen, tc.length, func(ctx context.Context, joined string) (string, error) {
+				sent = append(sent, joined)
+				return strings.ToUpper(joined), nil
+			})
+			require.Equal(t, tc.sent, sent)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.Nil(t, err)
+			for i, text := range tc.texts {
+				require.Equal(t, strings.ToUpper(text), result[i])
+			}
+		})
+	}
+}
@@ -2,11 +2,11 @@ package go_translate
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"testing"
-	"time"
 
+	"github.com/dinhcanh303/go_translate/translatetest"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -20,19 +20,12 @@ func TestTranslateBatchText(t *testing.T) {
 	}
 	tcs := map[string]TranslateTestCase{
 		"google case 1": {
-			opts: &TranslateOptions{Provider: ProviderMix,
+			opts: &TranslateOptions{Provider: ProviderGoogle,
 				GoogleAPIType:        TypeDictionary,
 				UseRandomUserAgents:  true,
 				UseRandomServiceUrls: true,
 				AddToken:             true,
-				HTTPClient: &http.Client{
-					Timeout: 15 * time.Second,
-					Transport: &http.Transport{
-						MaxIdleConns:        2000,
-						MaxIdleConnsPerHost: 2000,
-						IdleConnTimeout:     100 * time.Second,
-					},
-				}},
+			},
 			input:          []string{"Thank you for using our package.", "한국어", "I'm fine", "我认为我们需要拭目以待。美联储加息可能会让市场更加动荡😑😑😑😑"},
 			detectedLang:   "auto",
 			targetLang:     "vi",
@@ -53,18 +46,89 @@ func TestTranslateBatchText(t *testing.T) {
 			expectedOutput: []string{"Cảm ơn bạn đã sử dụng gói của chúng tôi.", "Tôi khỏe"},
 		},
 	}
+	// Responses are replayed from a cassette, so every scenario pins its provider and API type.
+	recorder := newRecorder(t, "testdata/cassettes/translate_batch_text.json")
 	ctx := context.Background()
 	for scenario, tc := range tcs {
 		tc := tc
 		t.Run(scenario, func(t *testing.T) {
+			tc.opts.HTTPClient = recorder.Client()
 			translator, err := NewTranslator(tc.opts)
 			require.Nil(t, err)
 			require.NotNil(t, translator)
 
-			fmt.Println(tc.input)
 			result, err := translator.TranslateText(ctx, tc.input, tc.targetLang)
 			require.Nil(t, err)
 			require.Equal(t, tc.expectedOutput, result)
 		})
 	}
 }
+
+func TestTranslateAPITypes(t *testing.T) {
+	recorder := newRecorder(t, "testdata/cassettes/api_types.json")
+	ctx := context.Background()
+	input := []string{"Hello", "How are you?"}
+	expected := []string{"Xin chào", "Bạn khỏe không?"}
+	for _, apiType := range GoogleAPITypeSupport {
+		t.Run(string(apiType), func(t *testing.T) {
+			translator, err := NewTranslator(&TranslateOptions{Provider: ProviderGoogle, GoogleAPIType: apiType, AddToken: true, HTTPClient: recorder.Client()})
+			require.Nil(t, err)
+			result, err := translator.TranslateText(ctx, input, "vi")
+			require.Nil(t, err)
+			require.Equal(t, expected, result)
+		})
+	}
+	for _, apiType := range []MicrosoftAPIType{TypeEdge, TypeSmartLink} {
+		t.Run(string(apiType), func(t *testing.T) {
+			translator, err := NewTranslator(&TranslateOptions{Provider: ProviderMicrosoft, MicrosoftAPIType: apiType, HTTPClient: recorder.Client()})
+			require.Nil(t, err)
+			result, err := translator.TranslateText(ctx, input, "vi", "en")
+			require.Nil(t, err)
+			require.Equal(t, expected, result)
+		})
+	}
+}
+
+// newRecorder replays the cassette at path, or records it when GO_TRANSLATE_RECORD=1.
+// The checked-in cassettes are hand-written from the shapes of the live responses, not recorded traffic;
+// run GO_TRANSLATE_RECORD=1 go test -run 'TestTranslateBatchText|TestTranslateAPITypes' to replace them with recordings.
+func newRecorder(t *testing.T, path string) *translatetest.Recorder {
+	recorder, err := translatetest.NewRecorder(path, translatetest.ModeFromEnv(), nil)
+	require.Nil(t, err)
+	t.Cleanup(func() { require.Nil(t, recorder.Save()) })
+	return recorder
+}
+
+func TestEndpointOverrides(t *testing.T) {
+	server := newFixtureServer(t, map[string]fixtureRoute{
+		"/gtx":       {fixture: "endpoint_gtx.json"},
+		"/pa":        {fixture: "endpoint_pa.json"},
+		"/auth":      {fixture: "endpoint_auth.txt"},
+		"/edge":      {fixture: "endpoint_edge.json", check: func(r *http.Request) { assert.Equal(t, "vi", r.URL.Query().Get("to")) }},
+		"/smartlink": {fixture: "endpoint_smartlink.txt"},
+	})
+	ctx := context.Background()
+	input := []string{"Hello", "How are you?"}
+	expected := []string{"Xin chào", "Bạn khỏe không?"}
+
+	tcs := map[string]*TranslateOptions{
+		"google client-gtx": {Provider: ProviderGoogle, GoogleAPIType: TypeClientGtx, GoogleEndpoints: map[GoogleAPIType]string{TypeClientGtx: server.URL + "/gtx?client=gtx&sl=auto&dt=t"}},
+		"google pa-gtx":     {Provider: ProviderGoogle, GoogleAPIType: TypePaGtx, GoogleEndpoints: map[GoogleAPIType]string{TypePaGtx: server.URL + "/pa"}},
+		"microsoft edge": {Provider: ProviderMicrosoft, MicrosoftAPIType: TypeEdge, MicrosoftAuthURL: server.URL + "/auth",
+			MicrosoftEndpoints: map[MicrosoftAPIType]string{TypeEdge: server.URL + "/edge?api-version=3.0&to="}},
+		"microsoft smart-link": {Provider: ProviderMicrosoft, MicrosoftAPIType: TypeSmartLink,
+			MicrosoftEndpoints: map[MicrosoftAPIType]string{TypeSmartLink: server.URL + "/smartlink"}},
+	}
+	for scenario, opts := range tcs {
+		opts := opts
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+			translator, err := NewTranslator(opts)
+			require.Nil(t, err)
+			result, err := translator.TranslateText(ctx, input, "vi")
+			require.Nil(t, err)
+			require.Equal(t, expected, result)
+		})
+	}
+	require.Equal(t, "https://translate-pa.googleapis.com/v1/translate?params.client=gtx&query.source_language=auto&data_types=TRANSLATION&data_types=SENTENCE_SPLITS&data_types=BILINGUAL_DICTIONARY_FULL", GoogleUrls[TypePaGtx])
+}
@@ -0,0 +1,98 @@
+// Package translatetest provides helpers for testing code that uses go_translate without network access:
+// a programmable fake Translator and a record/replay http.RoundTripper that keeps credentials out of cassettes.
+//
+// The cassettes of the go_translate tests, under testdata/cassettes, are synthetic: they were written by hand
+// from the documented formats, not recorded from the real services, as their comment field states.
+package translatetest
+
+import (
+	"context"
+	"sync"
+)
+
+// Call records the arguments of one TranslateText call made on a Fake.
+type Call struct {
+	Texts            []string
+	Target           string
+	DetectedLangCode []string
+}
+
+// Fake is a programmable Translator for tests.
+//
+// By default every text is translated to "[target] text". Use Set to program exact translations,
+// Func to compute them, and FailNext to make the following calls fail.
+// A Fake is safe for concurrent use.
+type Fake struct {
+	// Func, if set, computes the translations of every call that is not failed by FailNext.
+	Func func(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error)
+
+	mu           sync.Mutex
+	translations map[string]string
+	errs         []error
+	calls        []Call
+}
+
+// NewFake returns a Fake with no programmed translations.
+func NewFake() *Fake {
+	return &Fake{translations: map[string]string{}}
+}
+
+// Set programs the translation of text into target and returns the Fake for chaining.
+func (f *Fake) Set(text, target, translation string) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.translations == nil {
+		f.translations = map[string]string{}
+	}
+	f.translations[target+"\x00"+text] = translation
+	return f
+}
+
+// FailNext makes the next len(errs) calls return the given errors in order.
+func (f *Fake) FailNext(errs ...error) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errs = append(f.errs, errs...)
+	return f
+}
+
+// Calls returns the calls made so far.
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// TranslateText implements go_translate.Translator.
+func (f *Fake) TranslateText(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, Call{
+		Texts:            append([]string(nil), texts...),
+		Target:           target,
+		DetectedLangCode: append([]string(nil), detectedLangCode...),
+	})
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		f.mu.Unlock()
+		return nil, err
+	}
+	f.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if f.Func != nil {
+		return f.Func(ctx, texts, target, detectedLangCode...)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	results := make([]string, len(texts))
+	for i, text := range texts {
+		if translation, ok := f.translations[target+"\x00"+text]; ok {
+			results[i] = translation
+			continue
+		}
+		results[i] = "[" + target + "] " + text
+	}
+	return results, nil
+}
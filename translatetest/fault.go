@@ -0,0 +1,214 @@
+package translatetest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/dinhcanh303/go_translate/utils"
+)
+
+// FaultKind is a kind of misbehaviour injected by a FaultInjector.
+type FaultKind int
+
+const (
+	// FaultLatency delays the request by Fault.Latency, then lets the following faults or the transport handle it.
+	FaultLatency FaultKind = iota
+
+	// FaultTimeout fails the request with a net.Error whose Timeout method reports true, after Fault.Latency.
+	FaultTimeout
+
+	// FaultReset fails the request with a "connection reset by peer" error.
+	FaultReset
+
+	// FaultStatus answers with Fault.StatusCode without contacting the server.
+	FaultStatus
+
+	// FaultTruncate forwards the request and cuts the response body in half, ending it with io.ErrUnexpectedEOF.
+	FaultTruncate
+
+	// FaultMalformedJSON forwards the request and replaces the response body with invalid JSON.
+	FaultMalformedJSON
+)
+
+// Fault describes one misbehaviour and the requests it applies to.
+// A request matches when every non-empty selector is equal to the corresponding request field.
+type Fault struct {
+	Kind FaultKind
+
+	// Host selects requests by URL host (e.g. "translate-pa.googleapis.com").
+	Host string
+
+	// Provider and APIType select requests by the utils.RequestInfo carried by their context (e.g. "google", "pa-gtx").
+	Provider string
+	APIType  string
+
+	// Probability is the chance, between 0 and 1, that a matching request is affected. Zero means always.
+	Probability float64
+
+	// Latency is the delay of FaultLatency and FaultTimeout.
+	Latency time.Duration
+
+	// StatusCode is the status answered by FaultStatus (default 503).
+	StatusCode int
+}
+
+// FaultInjector is an http.RoundTripper that injects faults into the requests sent through it.
+// Faults are evaluated in order; the first fault that fires, other than FaultLatency, decides the outcome.
+type FaultInjector struct {
+	transport http.RoundTripper
+	faults    []Fault
+
+	mu       sync.Mutex
+	rand     *rand.Rand
+	injected map[FaultKind]int
+}
+
+// NewFaultInjector wraps transport (http.DefaultTransport if nil). The seed makes probabilistic faults reproducible.
+func NewFaultInjector(transport http.RoundTripper, seed int64, faults ...Fault) *FaultInjector {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &FaultInjector{
+		transport: transport,
+		faults:    faults,
+		rand:      rand.New(rand.NewSource(seed)),
+		injected:  map[FaultKind]int{},
+	}
+}
+
+// Client returns an HTTP client using the FaultInjector as its transport.
+func (f *FaultInjector) Client() *http.Client {
+	return &http.Client{Transport: f}
+}
+
+// Injected returns how many times faults of the given kind were injected.
+func (f *FaultInjector) Injected(kind FaultKind) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.injected[kind]
+}
+
+// RoundTrip implements http.RoundTripper.
+func (f *FaultInjector) RoundTrip(req *http.Request) (*http.Response, error) {
+	for _, fault := range f.faults {
+		if !f.fires(fault, req) {
+			continue
+		}
+		switch fault.Kind {
+		case FaultLatency:
+			if err := sleep(req, fault.Latency); err != nil {
+				return nil, err
+			}
+			continue
+		case FaultTimeout:
+			if err := sleep(req, fault.Latency); err != nil {
+				return nil, err
+			}
+			return nil, &net.OpError{Op: "read", Net: "tcp", Err: timeoutError{}}
+		case FaultReset:
+			return nil, &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+		case FaultStatus:
+			status := fault.StatusCode
+			if status == 0 {
+				status = http.StatusServiceUnavailable
+			}
+			return newResponse(req, RecordedResponse{Status: status, Body: http.StatusText(status)}), nil
+		case FaultTruncate, FaultMalformedJSON:
+			return f.corrupt(req, fault.Kind)
+		}
+	}
+	return f.transport.RoundTrip(req)
+}
+
+// fires reports whether fault matches req and wins its probability roll, counting it if so.
+func (f *FaultInjector) fires(fault Fault, req *http.Request) bool {
+	info, _ := utils.RequestInfoFrom(req.Context())
+	if (fault.Host != "" && fault.Host != req.URL.Host) ||
+		(fault.Provider != "" && fault.Provider != info.Provider) ||
+		(fault.APIType != "" && fault.APIType != info.APIType) {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if fault.Probability > 0 && f.rand.Float64() >= fault.Probability {
+		return false
+	}
+	f.injected[fault.Kind]++
+	return true
+}
+
+// corrupt forwards req and damages the body of the response.
+func (f *FaultInjector) corrupt(req *http.Request, kind FaultKind) (*http.Response, error) {
+	resp, err := f.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if kind == FaultMalformedJSON {
+		body = []byte(`{"translation": [["unterminated`)
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	} else {
+		body = body[:len(body)/2]
+		resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{io.ErrUnexpectedEOF}))
+	}
+	resp.ContentLength = -1
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+func sleep(req *http.Request, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(d):
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// timeoutError is the net.Error returned by FaultTimeout.
+type timeoutError struct{}
+
+var _ net.Error = timeoutError{}
+
+func (timeoutError) Error() string   { return "i/o timeout (injected)" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// errReader is an io.Reader failing with err.
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+// String returns the name of the fault kind.
+func (k FaultKind) String() string {
+	switch k {
+	case FaultLatency:
+		return "latency"
+	case FaultTimeout:
+		return "timeout"
+	case FaultReset:
+		return "reset"
+	case FaultStatus:
+		return "status"
+	case FaultTruncate:
+		return "truncate"
+	case FaultMalformedJSON:
+		return "malformed-json"
+	}
+	return fmt.Sprintf("FaultKind(%d)", int(k))
+}
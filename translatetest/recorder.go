@@ -0,0 +1,321 @@
+package translatetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Mode selects whether a Recorder replays a cassette or records a new one.
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette and never touches the network.
+	ModeReplay Mode = iota
+
+	// ModeRecord sends requests to the real transport and records every exchange.
+	ModeRecord
+)
+
+// RecordEnv is the environment variable that switches ModeFromEnv to ModeRecord when set to "1".
+const RecordEnv = "GO_TRANSLATE_RECORD"
+
+// ModeFromEnv returns ModeRecord if RecordEnv is set to "1", ModeReplay otherwise.
+func ModeFromEnv() Mode {
+	if os.Getenv(RecordEnv) == "1" {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// IgnoredQueryParams lists the query parameters left out when matching requests,
+// because they change between runs (the Google "tk" token is derived from a rotating key).
+var IgnoredQueryParams = []string{"tk"}
+
+// Redacted replaces the credentials in recorded requests.
+const Redacted = "REDACTED"
+
+// RedactedHeaders lists the request headers carrying credentials. Their values are saved as Redacted.
+var RedactedHeaders = []string{
+	"Authorization", "Proxy-Authorization", "Cookie",
+	"X-Goog-Api-Key", "Ocp-Apim-Subscription-Key", "X-Ncp-Apigw-Api-Key-Id", "X-Ncp-Apigw-Api-Key",
+}
+
+// RedactedParams lists the query, form and top-level JSON body parameters carrying credentials or derived from
+// them, such as the Baidu signature and its salt. Their values are saved as Redacted and ignored when matching.
+var RedactedParams = []string{"key", "api_key", "auth_key", "appid", "sign", "salt"}
+
+// Cassette is the on-disk list of recorded HTTP exchanges.
+type Cassette struct {
+	// Comment describes the cassette, e.g. how it was made. It is not used by the Recorder.
+	Comment string `json:"comment,omitempty"`
+
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the part of a request used for matching. Headers are kept for reference only.
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a recorded response.
+type RecordedResponse struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body"`
+}
+
+// Recorder is an http.RoundTripper that records exchanges to a cassette file or replays them.
+//
+// Requests are matched on method, path, query and body. The scheme, host, headers (such as randomized
+// User-Agents or auth tokens), the IgnoredQueryParams and the RedactedParams are not compared, so requests
+// sent to random Google mirrors or with other credentials replay deterministically.
+// Recorded requests never hold the values of the RedactedHeaders and RedactedParams.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// NewRecorder creates a Recorder for the cassette at path. In ModeReplay the cassette must exist;
+// in ModeRecord requests are sent with transport (http.DefaultTransport if nil) and Save writes the cassette.
+func NewRecorder(path string, mode Mode, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	r := &Recorder{path: path, mode: mode, transport: transport}
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Client returns an HTTP client using the Recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := redact(RecordedRequest{Method: req.Method, URL: req.URL.String(), Headers: req.Header.Clone(), Body: string(body)})
+	if r.mode == ModeRecord {
+		return r.record(req, recorded)
+	}
+	return r.replay(req, recorded)
+}
+
+func (r *Recorder) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	// Let the transport negotiate compression so that cassettes hold decoded bodies.
+	req = req.Clone(req.Context())
+	req.Header.Del("Accept-Encoding")
+	recorded.Headers.Del("Accept-Encoding")
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request:  recorded,
+		Response: RecordedResponse{Status: resp.StatusCode, Headers: resp.Header.Clone(), Body: string(respBody)},
+	})
+	r.mu.Unlock()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+// replay serves the first unused matching interaction, or the last matching one once all have been used.
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	key, err := matchKey(recorded)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	found := -1
+	for i, interaction := range r.cassette.Interactions {
+		candidate, err := matchKey(redact(interaction.Request))
+		if err != nil || candidate != key {
+			continue
+		}
+		found = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if found < 0 {
+		return nil, fmt.Errorf("translatetest: no recorded interaction in %s for %s %s", r.path, req.Method, req.URL)
+	}
+	r.used[found] = true
+	return newResponse(req, r.cassette.Interactions[found].Response), nil
+}
+
+// Save writes the recorded interactions to the cassette file. It does nothing in ModeReplay.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	r.mu.Lock()
+	err := encoder.Encode(r.cassette)
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, buf.Bytes(), 0o644)
+}
+
+// matchKey reduces a request to the fields compared during replay.
+func matchKey(req RecordedRequest) (string, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	for _, param := range append(IgnoredQueryParams, RedactedParams...) {
+		query.Del(param)
+	}
+	return req.Method + " " + u.Path + "?" + query.Encode() + "\n" + req.Body, nil
+}
+
+// redact returns a copy of req whose credential headers and parameters are replaced with Redacted.
+func redact(req RecordedRequest) RecordedRequest {
+	if len(req.Headers) > 0 {
+		req.Headers = req.Headers.Clone()
+		for _, name := range RedactedHeaders {
+			if _, ok := req.Headers[http.CanonicalHeaderKey(name)]; ok {
+				req.Headers.Set(name, Redacted)
+			}
+		}
+	}
+	if u, err := url.Parse(req.URL); err == nil {
+		if query, ok := redactValues(u.Query()); ok {
+			u.RawQuery = query.Encode()
+			req.URL = u.String()
+		}
+	}
+	req.Body = redactBody(req.Body)
+	return req
+}
+
+// redactBody redacts the RedactedParams of a form or JSON object body. Other bodies are returned unchanged,
+// as are the bodies without any of the parameters.
+func redactBody(body string) string {
+	if strings.HasPrefix(body, "{") {
+		var object map[string]json.RawMessage
+		if json.Unmarshal([]byte(body), &object) != nil {
+			return body
+		}
+		redacted := false
+		for _, param := range RedactedParams {
+			if _, ok := object[param]; ok {
+				object[param], redacted = json.RawMessage(strconv.Quote(Redacted)), true
+			}
+		}
+		if !redacted {
+			return body
+		}
+		data, err := json.Marshal(object)
+		if err != nil {
+			return body
+		}
+		return string(data)
+	}
+	form, err := url.ParseQuery(body)
+	if err != nil {
+		return body
+	}
+	if form, ok := redactValues(form); ok {
+		return form.Encode()
+	}
+	return body
+}
+
+// redactValues replaces the RedactedParams of values, reporting whether there was any.
+func redactValues(values url.Values) (url.Values, bool) {
+	redacted := false
+	for _, param := range RedactedParams {
+		if _, ok := values[param]; ok {
+			values.Set(param, Redacted)
+			redacted = true
+		}
+	}
+	return values, redacted
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func newResponse(req *http.Request, recorded RecordedResponse) *http.Response {
+	status := recorded.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	header := recorded.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(recorded.Body))),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,140 @@
+package translatetest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	go_translate "github.com/dinhcanh303/go_translate"
+	"github.com/dinhcanh303/go_translate/translatetest"
+	"github.com/dinhcanh303/go_translate/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var _ go_translate.Translator = (*translatetest.Fake)(nil)
+
+func TestFake(t *testing.T) {
+	ctx := context.Background()
+	fake := translatetest.NewFake().Set("Hello", "vi", "Xin chào")
+	failure := errors.New("boom")
+	fake.FailNext(failure)
+
+	_, err := fake.TranslateText(ctx, []string{"Hello"}, "vi")
+	require.Equal(t, failure, err)
+
+	result, err := fake.TranslateText(ctx, []string{"Hello", "Bye"}, "vi", "en")
+	require.Nil(t, err)
+	require.Equal(t, []string{"Xin chào", "[vi] Bye"}, result)
+
+	calls := fake.Calls()
+	require.Len(t, calls, 2)
+	require.Equal(t, []string{"en"}, calls[1].DetectedLangCode)
+}
+
+func TestRecorderReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	cassette := `{"interactions":[
+		{"request":{"method":"GET","url":"https://a.example.com/translate?q=Hello&tk=1.1"},"response":{"status":200,"body":"first"}},
+		{"request":{"method":"GET","url":"https://a.example.com/translate?q=Hello&tk=1.1"},"response":{"status":200,"body":"second"}}
+	]}`
+	require.Nil(t, os.WriteFile(path, []byte(cassette), 0o644))
+
+	recorder, err := translatetest.NewRecorder(path, translatetest.ModeReplay, nil)
+	require.Nil(t, err)
+	client := recorder.Client()
+
+	get := func(url string) string {
+		req, err := http.NewRequest("GET", url, nil)
+		require.Nil(t, err)
+		req.Header.Set("User-Agent", "random")
+		resp, err := client.Do(req)
+		require.Nil(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.Nil(t, err)
+		return string(body)
+	}
+	// Host, headers and the tk token are ignored; interactions are served in order, then the last one repeats.
+	require.Equal(t, "first", get("https://b.example.com/translate?q=Hello&tk=2.2"))
+	require.Equal(t, "second", get("https://c.example.com/translate?tk=3.3&q=Hello"))
+	require.Equal(t, "second", get("https://a.example.com/translate?q=Hello"))
+
+	_, err = client.Get("https://a.example.com/translate?q=Bye")
+	require.NotNil(t, err)
+	require.True(t, strings.Contains(err.Error(), "no recorded interaction"))
+}
+
+func TestRecorderRedactsCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	send := func(client *http.Client, secret string) string {
+		form := url.Values{"q": {"Hello"}, "appid": {secret}, "salt": {secret}, "sign": {secret}}
+		req, err := http.NewRequest("POST", server.URL+"/translate?key="+secret+"&q=Hello", strings.NewReader(form.Encode()))
+		require.Nil(t, err)
+		req.Header.Set("Authorization", "Bearer "+secret)
+		req.Header.Set("X-Goog-API-Key", secret)
+		resp, err := client.Do(req)
+		require.Nil(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.Nil(t, err)
+		return string(body)
+	}
+	recorder, err := translatetest.NewRecorder(path, translatetest.ModeRecord, nil)
+	require.Nil(t, err)
+	require.Equal(t, "ok", send(recorder.Client(), "secret-1"))
+	require.Nil(t, recorder.Save())
+
+	data, err := os.ReadFile(path)
+	require.Nil(t, err)
+	require.NotContains(t, string(data), "secret-1")
+	require.Contains(t, string(data), translatetest.Redacted)
+
+	// Other credentials replay the same interaction.
+	recorder, err = translatetest.NewRecorder(path, translatetest.ModeReplay, nil)
+	require.Nil(t, err)
+	require.Equal(t, "ok", send(recorder.Client(), "secret-2"))
+}
+
+func TestFaultInjector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"translation":"Xin chào"}`)
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	injector := translatetest.NewFaultInjector(nil, 1,
+		translatetest.Fault{Kind: translatetest.FaultStatus, Host: "other.example.com"},
+		translatetest.Fault{Kind: translatetest.FaultTruncate, Host: host, APIType: "pa-gtx"},
+		translatetest.Fault{Kind: translatetest.FaultReset, Provider: "microsoft"},
+	)
+	client := injector.Client()
+	ctx := utils.WithRequestInfo(context.Background(), utils.RequestInfo{Provider: "google", APIType: "pa-gtx"})
+
+	_, err := utils.DoRequest(client, ctx, "GET", server.URL, nil, nil, nil)
+	require.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+
+	body, err := utils.DoRequest(client, context.Background(), "GET", server.URL, nil, nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, `{"translation":"Xin chào"}`, string(body))
+
+	ctx = utils.WithRequestInfo(context.Background(), utils.RequestInfo{Provider: "microsoft"})
+	_, err = utils.DoRequest(client, ctx, "GET", server.URL, nil, nil, nil)
+	require.True(t, errors.Is(err, syscall.ECONNRESET))
+
+	require.Equal(t, 0, injector.Injected(translatetest.FaultStatus))
+	require.Equal(t, 1, injector.Injected(translatetest.FaultTruncate))
+	require.Equal(t, 1, injector.Injected(translatetest.FaultReset))
+}
@@ -0,0 +1,22 @@
+package utils
+
+import "context"
+
+// RequestInfo describes which provider and API type an outgoing request belongs to.
+type RequestInfo struct {
+	Provider string // Provider name, e.g. "google"
+	APIType  string // API type of the provider, e.g. "pa-gtx" (empty when the provider has a single API)
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo returns a copy of ctx carrying info, so transports can tell requests apart.
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFrom returns the RequestInfo carried by ctx, if any.
+func RequestInfoFrom(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info, ok
+}
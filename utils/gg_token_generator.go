@@ -1,13 +1,53 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 	"unicode/utf8"
 )
 
-func GgTokenGenerate(text string) string {
-	tokenKeys := [2]int{406398, 2087938574}
+// TKK is the key pair Google Translate mixes into the "tk" token, published as "406398.2087938574".
+type TKK [2]int
+
+// defaultTKK is the key embedded in the library.
+var defaultTKK = TKK{406398, 2087938574}
+
+// DefaultTKK returns the key embedded in the library, used when no fresher key is available.
+func DefaultTKK() TKK {
+	return defaultTKK
+}
+
+// ParseTKK parses a key in its "406398.2087938574" form.
+func ParseTKK(value string) (TKK, error) {
+	first, second, ok := strings.Cut(strings.TrimSpace(value), ".")
+	if !ok {
+		return TKK{}, errors.New("invalid TKK: " + value)
+	}
+	key1, err := strconv.Atoi(first)
+	if err != nil {
+		return TKK{}, fmt.Errorf("invalid TKK %q: %w", value, err)
+	}
+	key2, err := strconv.Atoi(second)
+	if err != nil {
+		return TKK{}, fmt.Errorf("invalid TKK %q: %w", value, err)
+	}
+	return TKK{key1, key2}, nil
+}
+
+// String returns the key in its "406398.2087938574" form.
+func (k TKK) String() string {
+	return fmt.Sprintf("%d.%d", k[0], k[1])
+}
+
+// GgTokenGenerate computes the "tk" token of text with the given key (default DefaultTKK).
+func GgTokenGenerate(text string, tkk ...TKK) string {
+	tokenKeys := defaultTKK
+	if len(tkk) > 0 {
+		tokenKeys = tkk[0]
+	}
 	encodedChars := []int{}
 	for i := 0; i < getTextLength(text); i++ {
 		charCode := getCharCodeAt(text, i)
@@ -1,43 +1,210 @@
 package utils
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+
+	"github.com/andybalholm/brotli"
 )
 
-// It returns the response body as a byte slice or an error if the request fails.
-func DoRequest(client *http.Client, ctx context.Context, method, endpoint string, headers map[string]string, params url.Values, body []byte) ([]byte, error) {
+// DefaultMaxResponseSize is the maximum size of a decoded response body when RequestOptions.MaxResponseSize is not set.
+const DefaultMaxResponseSize = 10 << 20
+
+// ErrResponseTooLarge is returned when a response body exceeds the maximum response size.
+var ErrResponseTooLarge = errors.New("response body exceeds the maximum size")
+
+// ErrBlocked is matched by errors.Is for a BlockedError.
+var ErrBlocked = errors.New("request blocked")
+
+// RequestOptions tunes how DoRequest and DoRequestJSON read responses.
+type RequestOptions struct {
+	// MaxResponseSize limits the decoded response body, in bytes (default DefaultMaxResponseSize).
+	MaxResponseSize int64
+}
+
+// DoRequest sends a request with client and returns the response body, or an error if the request fails.
+// client is usually an *http.Client, possibly wrapped with Chain.
+func DoRequest(client Doer, ctx context.Context, method, endpoint string, headers map[string]string, params url.Values, body []byte, opts ...RequestOptions) ([]byte, error) {
+	var respBody []byte
+	err := send(client, ctx, method, endpoint, headers, params, body, opts, func(r io.Reader) error {
+		var err error
+		respBody, err = io.ReadAll(r)
+		return err
+	})
+	return respBody, err
+}
+
+// DoRequestJSON sends a request like DoRequest and decodes the JSON response body into out while it is read.
+func DoRequestJSON(client Doer, ctx context.Context, method, endpoint string, headers map[string]string, params url.Values, body []byte, out any, opts ...RequestOptions) error {
+	return send(client, ctx, method, endpoint, headers, params, body, opts, func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(out)
+	})
+}
+
+// send performs the request and hands the decompressed, size-limited body of a successful response to read.
+func send(client Doer, ctx context.Context, method, endpoint string, headers map[string]string, params url.Values, body []byte, opts []RequestOptions, read func(io.Reader) error) error {
 	reqURL := buildRequestURL(endpoint, params)
 	reqBody := buildRequestBody(body)
 
 	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	for k, v := range headers {
 		req.Header.Set(k, v)
 	}
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", "gzip, br")
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
+	decoded, err := decodeBody(resp)
+	if err != nil {
+		return err
+	}
+	maxSize := int64(DefaultMaxResponseSize)
+	if len(opts) > 0 && opts[0].MaxResponseSize > 0 {
+		maxSize = opts[0].MaxResponseSize
+	}
+	resp.Body = io.NopCloser(&limitedReader{r: decoded, n: maxSize})
+
 	if err := handleHTTPError(resp); err != nil {
-		return nil, err
+		return err
+	}
+	sniffed := bufio.NewReaderSize(resp.Body, sniffSize)
+	if err := checkBlocked(resp, sniffed); err != nil {
+		return err
+	}
+	return read(sniffed)
+}
+
+// decodeBody undoes the Content-Encoding of the response body.
+func decodeBody(resp *http.Response) (io.Reader, error) {
+	switch encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))); encoding {
+	case "", "identity":
+		return resp.Body, nil
+	case "gzip", "x-gzip":
+		return gzip.NewReader(resp.Body)
+	case "br":
+		return brotli.NewReader(resp.Body), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
 	}
-	return io.ReadAll(resp.Body)
 }
 
+// LimitBody wraps body so that reading more than max bytes fails with ErrResponseTooLarge.
+func LimitBody(body io.ReadCloser, max int64) io.ReadCloser {
+	return struct {
+		io.Reader
+		io.Closer
+	}{&limitedReader{r: body, n: max}, body}
+}
+
+// limitedReader fails with ErrResponseTooLarge once more than n bytes have been read.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n, ErrResponseTooLarge
+	}
+	return n, err
+}
+
+// HTTPError is returned by DoRequest when the server answers with a non-2xx status code.
+type HTTPError struct {
+	StatusCode int    // HTTP status code of the response
+	Body       []byte // Response body, truncated to maxErrorBodySize bytes
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP error: status code %d", e.StatusCode)
+}
+
+// maxErrorBodySize limits how much of an error response body is kept in HTTPError.
+const maxErrorBodySize = 4 << 10
+
 // handleHTTPError checks the HTTP response status and returns an error if it's not successful.
 func handleHTTPError(resp *http.Response) error {
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("HTTP error: status code %d", resp.StatusCode)
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return &HTTPError{StatusCode: resp.StatusCode, Body: body}
+	}
+	return nil
+}
+
+// BlockedError is returned when a request is answered with a captcha or consent page instead of the expected data.
+type BlockedError struct {
+	URL     string // URL of the page that was served
+	Excerpt string // Beginning of the page
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("request blocked: %s served a captcha or consent page", e.URL)
+}
+
+// Is reports whether target is ErrBlocked.
+func (e *BlockedError) Is(target error) bool {
+	return target == ErrBlocked
+}
+
+// sniffSize is how much of a response body is inspected for a captcha or consent page.
+const sniffSize = 4 << 10
+
+// blockedMarkers are found in the captcha and consent pages served to blocked clients.
+var blockedMarkers = []string{
+	"unusual traffic",
+	"captcha",
+	"consent.google.",
+	"/sorry/index",
+	"before you continue",
+}
+
+// checkBlocked reports a BlockedError if the request was redirected to a captcha or consent page,
+// or if the body is an HTML document mentioning one.
+func checkBlocked(resp *http.Response, body *bufio.Reader) error {
+	pageURL := ""
+	if resp.Request != nil && resp.Request.URL != nil {
+		u := resp.Request.URL
+		pageURL = u.String()
+		if strings.HasPrefix(u.Host, "consent.") || strings.HasPrefix(u.Path, "/sorry/") {
+			peek, _ := body.Peek(sniffSize)
+			return &BlockedError{URL: pageURL, Excerpt: excerpt(peek)}
+		}
+	}
+	peek, _ := body.Peek(sniffSize)
+	page := strings.ToLower(strings.TrimSpace(string(peek)))
+	if !strings.HasPrefix(page, "<!doctype html") && !strings.HasPrefix(page, "<html") {
+		return nil
+	}
+	for _, marker := range blockedMarkers {
+		if strings.Contains(page, marker) {
+			return &BlockedError{URL: pageURL, Excerpt: excerpt(peek)}
+		}
 	}
 	return nil
 }
@@ -48,6 +215,10 @@ func buildRequestURL(endpoint string, params url.Values) string {
 		return endpoint
 	}
 	u, _ := url.Parse(endpoint)
+	if u.RawQuery == "" {
+		u.RawQuery = params.Encode()
+		return u.String()
+	}
 	u.RawQuery = u.Query().Encode() + "&" + params.Encode()
 	return u.String()
 }
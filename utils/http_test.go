@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoRequestDecodesCompressedBodies(t *testing.T) {
+	payload := `{"translation":"Xin chào"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		switch r.URL.Path {
+		case "/gzip":
+			require.Contains(t, r.Header.Get("Accept-Encoding"), "gzip")
+			zw := gzip.NewWriter(&buf)
+			zw.Write([]byte(payload))
+			zw.Close()
+			w.Header().Set("Content-Encoding", "gzip")
+		case "/br":
+			require.Contains(t, r.Header.Get("Accept-Encoding"), "br")
+			bw := brotli.NewWriter(&buf)
+			bw.Write([]byte(payload))
+			bw.Close()
+			w.Header().Set("Content-Encoding", "br")
+		default:
+			buf.WriteString(payload)
+		}
+		w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	for _, path := range []string{"/gzip", "/br", "/identity"} {
+		body, err := DoRequest(server.Client(), context.Background(), "GET", server.URL+path, nil, nil, nil)
+		require.Nil(t, err, path)
+		require.Equal(t, payload, string(body), path)
+
+		var out struct {
+			Translation string `json:"translation"`
+		}
+		require.Nil(t, DoRequestJSON(server.Client(), context.Background(), "GET", server.URL+path, nil, nil, nil, &out), path)
+		require.Equal(t, "Xin chào", out.Translation, path)
+	}
+}
+
+func TestDoRequestMaxResponseSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"translation":"` + strings.Repeat("a", 100) + `"}`))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	_, err := DoRequest(server.Client(), ctx, "GET", server.URL, nil, nil, nil, RequestOptions{MaxResponseSize: 64})
+	require.True(t, errors.Is(err, ErrResponseTooLarge))
+
+	var out map[string]string
+	err = DoRequestJSON(server.Client(), ctx, "GET", server.URL, nil, nil, nil, &out, RequestOptions{MaxResponseSize: 64})
+	require.True(t, errors.Is(err, ErrResponseTooLarge))
+
+	body, err := DoRequest(server.Client(), ctx, "GET", server.URL, nil, nil, nil, RequestOptions{MaxResponseSize: 118})
+	require.Nil(t, err)
+	require.Len(t, body, 118)
+}
+
+func TestDoRequestDetectsBlockedPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/captcha":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<!DOCTYPE html><html><body>Our systems have detected unusual traffic from your computer network.</body></html>"))
+		case "/redirect":
+			http.Redirect(w, r, "/sorry/index?continue=x", http.StatusFound)
+		case "/sorry/index":
+			w.Write([]byte("please solve the challenge"))
+		default:
+			// Smart-link answers plain text with an HTML content type; it must not be mistaken for a block page.
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`Xin chào captcha`))
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	for _, path := range []string{"/captcha", "/redirect"} {
+		_, err := DoRequest(server.Client(), ctx, "GET", server.URL+path, nil, nil, nil)
+		require.True(t, errors.Is(err, ErrBlocked), path)
+		var blocked *BlockedError
+		require.True(t, errors.As(err, &blocked), path)
+		require.NotEmpty(t, blocked.Excerpt, path)
+	}
+
+	body, err := DoRequest(server.Client(), ctx, "GET", server.URL+"/smart-link", nil, nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, `Xin chào captcha`, string(body))
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxExcerptSize limits how much of a response body is quoted in a ParseError.
+const maxExcerptSize = 256
+
+// ParseError is returned by the extractors when a response body does not have the expected shape.
+type ParseError struct {
+	Format  string // Response format being parsed, e.g. "client-gtx"
+	Reason  string // What was wrong with the body
+	Excerpt string // Beginning of the body, at most maxExcerptSize bytes
+	Err     error  // Underlying decoding error, if any
+}
+
+func (e *ParseError) Error() string {
+	msg := fmt.Sprintf("unexpected %s response: %s", e.Format, e.Reason)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg + fmt.Sprintf(" (body: %q)", e.Excerpt)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// newParseError builds a ParseError quoting the beginning of body.
+func newParseError(format string, body []byte, reason string, err error) *ParseError {
+	return &ParseError{Format: format, Reason: reason, Excerpt: excerpt(body), Err: err}
+}
+
+// excerpt returns the beginning of body, cut on a rune boundary.
+func excerpt(body []byte) string {
+	if len(body) <= maxExcerptSize {
+		return string(body)
+	}
+	cut := maxExcerptSize
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return string(body[:cut]) + "..."
+}
+
+// decodeJSON decodes body into v, reporting empty bodies and syntax or type errors as a ParseError.
+func decodeJSON(format string, body []byte, v any) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return newParseError(format, body, "empty body", nil)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return newParseError(format, body, "invalid JSON", err)
+	}
+	return nil
+}
+
+// firstString returns the first string found in v, descending into the first element of nested arrays.
+// Nulls and other types yield false.
+func firstString(v any) (string, bool) {
+	for depth := 0; depth < 8; depth++ {
+		switch value := v.(type) {
+		case string:
+			return value, true
+		case []any:
+			if len(value) == 0 {
+				return "", false
+			}
+			v = value[0]
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// unescapeText decodes the HTML entities (e.g. "&#39;") the plain text endpoints sometimes return.
+func unescapeText(text string) string {
+	if !strings.Contains(text, "&") {
+		return text
+	}
+	return html.UnescapeString(text)
+}
@@ -2,40 +2,54 @@ package utils
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"math/rand"
 	"strings"
 )
 
-// ExtractTranslatedTextFromHtml extracts the translated text from a given response body in JSON format.
-// The response body is expected to be a nested JSON array where the first element contains the translated text.
-// Returns the translated text or an error if the format is unexpected.
+// ExtractTranslatedTextFromHtml extracts the translated texts from a translateHtml response.
+// The response is expected to be a JSON array whose first element lists the translations, e.g. [["Xin chào"],["en"]].
+// Elements that are nested arrays yield their first string and nulls yield an empty string.
+// HTML entities are kept, since the endpoint translates HTML.
 func ExtractTranslatedTextFromHtml(respBody []byte) ([]string, error) {
-	var data [][]string
-	err := json.Unmarshal(respBody, &data)
-	if err != nil {
+	const format = "html"
+	var data []any
+	if err := decodeJSON(format, respBody, &data); err != nil {
 		return nil, err
 	}
-	if len(data) > 0 && len(data[0]) > 0 {
-		return data[0], nil
+	if len(data) == 0 {
+		return nil, newParseError(format, respBody, "empty array", nil)
+	}
+	items, ok := data[0].([]any)
+	if !ok {
+		if text, ok := data[0].(string); ok {
+			return []string{text}, nil
+		}
+		return nil, newParseError(format, respBody, "first element is not an array", nil)
+	}
+	if len(items) == 0 {
+		return nil, newParseError(format, respBody, "no translations", nil)
+	}
+	texts := make([]string, len(items))
+	for i, item := range items {
+		texts[i], _ = firstString(item)
 	}
-	return nil, errors.New("unexpected response format")
+	return texts, nil
 }
 
-// ExtractTranslatedText extracts the translated text from a given response body in JSON format.
-// The response body is expected to be a nested JSON array where the first element contains the translated text.
-// Returns the translated text or an error if the format is unexpected.
+// ExtractTranslatedText extracts the translated text from a translate_a/t response, e.g. [["Xin chào\nBạn khỏe không?","en"]].
+// The first string found in the first element is split into lines; a bare string or a flat array are accepted as well.
 func ExtractTranslatedText(respBody []byte) ([]string, error) {
-	var data [][]string
-	err := json.Unmarshal(respBody, &data)
-	if err != nil {
+	const format = "client-dict"
+	var data any
+	if err := decodeJSON(format, respBody, &data); err != nil {
 		return nil, err
 	}
-	if len(data) > 0 && len(data[0]) > 0 {
-		return SplitWithSeparator(data[0][0]), nil
+	text, ok := firstString(data)
+	if !ok {
+		return nil, newParseError(format, respBody, "no translated text", nil)
 	}
-	return nil, errors.New("unexpected response format")
+	return SplitWithSeparator(unescapeText(text)), nil
 }
 
 // DecodeUnicode decodes Unicode escape sequences in a string.
@@ -51,48 +65,56 @@ func DecodeUnicode(text string) (string, error) {
 	return output, nil
 }
 
-// ExtractTranslatedTextFromJson extracts the translated text from a JSON response with a "translation" field.
-// The response is expected to contain a "translation" field with the translated text.
-// Returns the translated text or an error if unmarshalling fails.
+// ExtractTranslatedTextFromJson extracts the translated text from a pa-gtx response with a "translation" field.
+// Unknown fields are ignored; a missing or non-string translation is reported as a ParseError.
 func ExtractTranslatedTextFromJson(respBody []byte) ([]string, error) {
+	const format = "pa-gtx"
 	var result struct {
-		Translation string `json:"translation"`
+		Translation any `json:"translation"`
 	}
-	err := json.Unmarshal(respBody, &result)
-	if err != nil {
+	if err := decodeJSON(format, respBody, &result); err != nil {
 		return nil, err
 	}
-	return SplitWithSeparator(result.Translation), nil
+	text, ok := result.Translation.(string)
+	if !ok {
+		return nil, newParseError(format, respBody, "missing translation field", nil)
+	}
+	return SplitWithSeparator(unescapeText(text)), nil
 }
 
-// ExtractTranslatedTextFromArray extracts the translated text from a JSON array where each element is a sentence.
-// The function expects the first layer of the JSON array to be a list of sentences.
-// It concatenates and returns the translated sentences as a single string.
+// ExtractTranslatedTextFromArray extracts the translated text from a translate_a/single response,
+// whose first element lists the sentences as [translation, source, ...] arrays.
+// The translations of all sentences are concatenated and split into lines; nulls and non-string segments are skipped.
 func ExtractTranslatedTextFromArray(data []byte) ([]string, error) {
-	var rawData []interface{}
-	if err := json.Unmarshal(data, &rawData); err != nil {
+	const format = "client-gtx"
+	var rawData []any
+	if err := decodeJSON(format, data, &rawData); err != nil {
 		return nil, err
 	}
-
-	sentences, ok := rawData[0].([]interface{})
+	if len(rawData) == 0 {
+		return nil, newParseError(format, data, "empty array", nil)
+	}
+	sentences, ok := rawData[0].([]any)
 	if !ok {
-		return nil, errors.New("unexpected format: cannot extract first layer")
+		return nil, newParseError(format, data, "first element is not a list of sentences", nil)
 	}
 
 	var builder strings.Builder
+	found := false
 	for _, item := range sentences {
-		segment, ok := item.([]interface{})
+		segment, ok := item.([]any)
 		if !ok || len(segment) == 0 {
 			continue
 		}
-
-		first, ok := segment[0].(string)
-		if ok {
+		if first, ok := segment[0].(string); ok {
 			builder.WriteString(first)
+			found = true
 		}
 	}
-
-	return SplitWithSeparator(builder.String()), nil
+	if !found {
+		return nil, newParseError(format, data, "no translated sentences", nil)
+	}
+	return SplitWithSeparator(unescapeText(builder.String())), nil
 }
 
 // GetRandomValue returns a random value from a slice of type T.
@@ -140,14 +162,19 @@ type Entry struct {
 	Translations []Translation `json:"translations"`
 }
 
+// ExtractTranslatedTextFromMCSEdge extracts the translations of a Microsoft translate response,
+// e.g. [{"translations":[{"text":"Xin chào","to":"vi"}]}]. Every item must carry at least one translation.
 func ExtractTranslatedTextFromMCSEdge(data []byte) ([]string, error) {
-	var entries []Entry
-	err := json.Unmarshal(data, &entries)
-	if err != nil {
+	const format = "edge"
+	var entries []*Entry
+	if err := decodeJSON(format, data, &entries); err != nil {
 		return nil, err
 	}
 	var texts []string
-	for _, entry := range entries {
+	for i, entry := range entries {
+		if entry == nil || len(entry.Translations) == 0 {
+			return nil, newParseError(format, data, fmt.Sprintf("no translation for item %d", i), nil)
+		}
 		for _, t := range entry.Translations {
 			texts = append(texts, t.Text)
 		}
@@ -167,10 +194,17 @@ type Response struct {
 	TranslateResponse TranslateResponse `json:"translateResponse"`
 }
 
+// ExtractTranslatedTextFromGGDic extracts the translated text from a dictionaryExtensionData response.
 func ExtractTranslatedTextFromGGDic(data []byte) ([]string, error) {
-	var res Response
-	if err := json.Unmarshal(data, &res); err != nil {
+	const format = "dictionary"
+	var res struct {
+		TranslateResponse *TranslateResponse `json:"translateResponse"`
+	}
+	if err := decodeJSON(format, data, &res); err != nil {
 		return nil, err
 	}
-	return SplitWithSeparator(res.TranslateResponse.TranslateText), nil
+	if res.TranslateResponse == nil {
+		return nil, newParseError(format, data, "missing translateResponse", nil)
+	}
+	return SplitWithSeparator(unescapeText(res.TranslateResponse.TranslateText)), nil
 }
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// extractors maps every response format to its extractor and golden fixture in testdata/golden.
+var extractors = map[string]func([]byte) ([]string, error){
+	"html":        ExtractTranslatedTextFromHtml,
+	"client-gtx":  ExtractTranslatedTextFromArray,
+	"client-dict": ExtractTranslatedText,
+	"pa-gtx":      ExtractTranslatedTextFromJson,
+	"dictionary":  ExtractTranslatedTextFromGGDic,
+	"edge":        ExtractTranslatedTextFromMCSEdge,
+}
+
+// goldenCase is one response body of a golden fixture and its expected extraction.
+type goldenCase struct {
+	Name  string   `json:"name"`
+	Body  string   `json:"body"`
+	Want  []string `json:"want"`
+	Error bool     `json:"error"`
+}
+
+func loadGolden(t testing.TB, format string) []goldenCase {
+	data, err := os.ReadFile(filepath.Join("testdata", "golden", format+".json"))
+	require.Nil(t, err)
+	var cases []goldenCase
+	require.Nil(t, json.Unmarshal(data, &cases))
+	return cases
+}
+
+func TestExtractorsGolden(t *testing.T) {
+	for format, extract := range extractors {
+		for _, tc := range loadGolden(t, format) {
+			tc, extract := tc, extract
+			t.Run(format+"/"+tc.Name, func(t *testing.T) {
+				result, err := extract([]byte(tc.Body))
+				if tc.Error {
+					var parseErr *ParseError
+					require.True(t, errors.As(err, &parseErr), "%v", err)
+					require.Equal(t, format, parseErr.Format)
+					return
+				}
+				require.Nil(t, err)
+				require.Equal(t, tc.Want, result)
+			})
+		}
+	}
+}
+
+func TestParseErrorExcerpt(t *testing.T) {
+	body := "<html>" + strings.Repeat("é", maxExcerptSize) + "</html>"
+	_, err := ExtractTranslatedTextFromArray([]byte(body))
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.True(t, strings.HasPrefix(parseErr.Excerpt, "<html>é"))
+	require.True(t, strings.HasSuffix(parseErr.Excerpt, "é..."))
+	require.LessOrEqual(t, len(parseErr.Excerpt), maxExcerptSize+3)
+	require.Contains(t, err.Error(), "unexpected client-gtx response: invalid JSON")
+}
+
+// fuzzExtractor checks that an extractor never panics and only fails with a ParseError.
+func fuzzExtractor(f *testing.F, format string) {
+	for _, tc := range loadGolden(f, format) {
+		f.Add([]byte(tc.Body))
+	}
+	extract := extractors[format]
+	f.Fuzz(func(t *testing.T, body []byte) {
+		_, err := extract(body)
+		if err != nil {
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("unexpected error type %T: %v", err, err)
+			}
+		}
+	})
+}
+
+func FuzzExtractTranslatedTextFromHtml(f *testing.F)    { fuzzExtractor(f, "html") }
+func FuzzExtractTranslatedTextFromArray(f *testing.F)   { fuzzExtractor(f, "client-gtx") }
+func FuzzExtractTranslatedText(f *testing.F)            { fuzzExtractor(f, "client-dict") }
+func FuzzExtractTranslatedTextFromJson(f *testing.F)    { fuzzExtractor(f, "pa-gtx") }
+func FuzzExtractTranslatedTextFromGGDic(f *testing.F)   { fuzzExtractor(f, "dictionary") }
+func FuzzExtractTranslatedTextFromMCSEdge(f *testing.F) { fuzzExtractor(f, "edge") }
+
+func TestParseTKK(t *testing.T) {
+	tkk, err := ParseTKK(" 406398.2087938574 ")
+	require.Nil(t, err)
+	require.Equal(t, DefaultTKK(), tkk)
+	require.Equal(t, "406398.2087938574", tkk.String())
+
+	for _, value := range []string{"", "406398", "a.1", "1.b"} {
+		_, err := ParseTKK(value)
+		require.NotNil(t, err, value)
+	}
+}
+
+func TestGgTokenGenerateKey(t *testing.T) {
+	require.Equal(t, GgTokenGenerate("Hello"), GgTokenGenerate("Hello", DefaultTKK()))
+	require.NotEqual(t, GgTokenGenerate("Hello"), GgTokenGenerate("Hello", TKK{445678, 1618375593}))
+}
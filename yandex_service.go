@@ -0,0 +1,150 @@
+package go_translate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const (
+	// YandexBaseUrl is the default endpoint of Yandex Cloud Translate.
+	YandexBaseUrl = "https://translate.api.cloud.yandex.net"
+
+	// YandexMaxChars is the maximum number of characters sent in one Yandex request.
+	YandexMaxChars = 10000
+)
+
+// YandexOptions configures ProviderYandex. Set it as TranslateOptions.ProviderOptions.
+type YandexOptions struct {
+	// APIKey authenticates with a service account API key ("Api-Key" authorization).
+	APIKey string
+
+	// IAMToken authenticates with an IAM token ("Bearer" authorization), used when APIKey is empty.
+	IAMToken string
+
+	// FolderID is the cloud folder; required with IAM tokens of user accounts.
+	FolderID string
+
+	// Format is "PLAIN_TEXT" (default) or "HTML".
+	Format string
+
+	// BaseURL overrides the endpoint (e.g., for a test server).
+	BaseURL string
+}
+
+// ProviderName implements ProviderOptions.
+func (YandexOptions) ProviderName() Provider {
+	return ProviderYandex
+}
+
+// YandexTranslateService is a Translator and Detector for Yandex Cloud Translate.
+type YandexTranslateService struct {
+	client *http.Client      // HTTP client used for making API requests
+	opts   *TranslateOptions // Options for configuring the translation service
+	yandex YandexOptions
+}
+
+// NewYandexTranslateService validates the YandexOptions carried by opts and creates the service.
+func NewYandexTranslateService(client *http.Client, opts *TranslateOptions) (*YandexTranslateService, error) {
+	yandex, _ := GetProviderOptions[YandexOptions](opts)
+	if yandex.APIKey == "" && yandex.IAMToken == "" {
+		return nil, errors.New("yandex requires an API key or an IAM token")
+	}
+	if yandex.BaseURL == "" {
+		yandex.BaseURL = YandexBaseUrl
+	}
+	yandex.BaseURL = strings.TrimRight(yandex.BaseURL, "/")
+	if yandex.Format == "" {
+		yandex.Format = "PLAIN_TEXT"
+	}
+	return &YandexTranslateService{client: client, opts: opts, yandex: yandex}, nil
+}
+
+// Capabilities reports the features supported by Yandex Cloud Translate.
+func (y *YandexTranslateService) Capabilities() Capabilities {
+	return Capabilities{Batch: true, Detection: true, HTML: true}
+}
+
+// TranslateText translates the texts with Yandex Cloud Translate in requests of at most YandexMaxChars characters.
+func (y *YandexTranslateService) TranslateText(ctx context.Context, texts []string, target string, detectedLangCode ...string) ([]string, error) {
+	payload := map[string]any{
+		"targetLanguageCode": yandexLanguageCode(target),
+		"format":             y.yandex.Format,
+	}
+	if source := sourceLanguage(detectedLangCode); source != "" {
+		payload["sourceLanguageCode"] = yandexLanguageCode(source)
+	}
+	if y.yandex.FolderID != "" {
+		payload["folderId"] = y.yandex.FolderID
+	}
+	results := make([]string, 0, len(texts))
+	for _, chunk := range chunkTexts(texts, len(texts), YandexMaxChars) {
+		payload["texts"] = texts[chunk.start:chunk.end]
+		var result struct {
+			Translations []struct {
+				Text string `json:"text"`
+			} `json:"translations"`
+		}
+		if err := y.call(ctx, "/translate/v2/translate", payload, &result); err != nil {
+			return nil, err
+		}
+		translated := make([]string, 0, len(result.Translations))
+		for _, t := range result.Translations {
+			translated = append(translated, t.Text)
+		}
+		translated, err := checkTranslationCount(texts[chunk.start:chunk.end], translated)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, translated...)
+	}
+	return results, nil
+}
+
+// DetectLanguage returns the detected language code of every text.
+func (y *YandexTranslateService) DetectLanguage(ctx context.Context, texts []string) ([]string, error) {
+	languages := make([]string, 0, len(texts))
+	for _, text := range texts {
+		payload := map[string]any{"text": text}
+		if y.yandex.FolderID != "" {
+			payload["folderId"] = y.yandex.FolderID
+		}
+		var result struct {
+			LanguageCode string `json:"languageCode"`
+		}
+		if err := y.call(ctx, "/translate/v2/detect", payload, &result); err != nil {
+			return nil, err
+		}
+		languages = append(languages, result.LanguageCode)
+	}
+	return languages, nil
+}
+
+func (y *YandexTranslateService) call(ctx context.Context, path string, payload any, out any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{"Content-Type": "application/json"}
+	if y.yandex.APIKey != "" {
+		headers["Authorization"] = "Api-Key " + y.yandex.APIKey
+	} else {
+		headers["Authorization"] = "Bearer " + y.yandex.IAMToken
+	}
+	if err := doRequestJSON(ctx, y.client, y.opts, "POST", y.yandex.BaseURL+path, headers, nil, body, out); err != nil {
+		return providerError(ProviderYandex, err)
+	}
+	return nil
+}
+
+// yandexLanguageCode maps a language tag to a Yandex language code.
+// Yandex uses ISO 639-1 codes, with "pt-BR" as the only regional variant.
+func yandexLanguageCode(code string) string {
+	base, rest := splitLanguageCode(code)
+	if base == "pt" && rest == "BR" {
+		return "pt-BR"
+	}
+	return base
+}
@@ -0,0 +1,45 @@
+package go_translate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYandexTranslate(t *testing.T) {
+	server := newFixtureServer(t, map[string]fixtureRoute{
+		"/translate/v2/translate": {fixture: "yandex_translate.json", check: func(r *http.Request) {
+			assert.Equal(t, "Api-Key yandex-key", r.Header.Get("Authorization"))
+			var payload map[string]any
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, "ru", payload["targetLanguageCode"])
+			assert.Equal(t, "en", payload["sourceLanguageCode"])
+			assert.Equal(t, "folder-1", payload["folderId"])
+			assert.Len(t, payload["texts"], 2)
+		}},
+		"/translate/v2/detect": {fixture: "yandex_detect.json"},
+	})
+
+	translator, err := NewTranslator(&TranslateOptions{
+		Provider:        ProviderYandex,
+		ProviderOptions: YandexOptions{APIKey: "yandex-key", FolderID: "folder-1", BaseURL: server.URL},
+	})
+	require.Nil(t, err)
+	result, err := translator.TranslateText(context.Background(), []string{"Hello world", "How are you?"}, "ru-RU", "en_US")
+	require.Nil(t, err)
+	require.Equal(t, []string{"Привет, мир", "Как дела?"}, result)
+
+	languages, err := translator.(Detector).DetectLanguage(context.Background(), []string{"Привет"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"ru"}, languages)
+}
+
+func TestYandexLanguageCode(t *testing.T) {
+	require.Equal(t, "zh", yandexLanguageCode("zh-Hans"))
+	require.Equal(t, "pt-BR", yandexLanguageCode("pt_br"))
+	require.Equal(t, "ru", yandexLanguageCode("RU"))
+}